@@ -2,6 +2,7 @@ package inmem
 
 import (
 	"errors"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -27,17 +28,56 @@ func (i item) isExpired() bool {
 // Cache is a generic cache implementation with support for time-to-live
 // (TTL) expiration.
 type Cache struct {
-	items map[string]item // The map storing cache items.
-	mu    sync.RWMutex    // Mutex for controlling concurrent access to the cache.
+	items  map[string]item // The map storing cache items.
+	mu     sync.RWMutex    // Mutex for controlling concurrent access to the cache.
+	jitter float64         // Fraction of TTL by which expiry is randomized.
+}
+
+// An Option configures a cache instance.
+type Option interface {
+	Apply(*Cache)
+}
+
+// OptionFunc is a function that configures a cache.
+type OptionFunc func(*Cache)
+
+// Apply calls f(cache).
+func (f OptionFunc) Apply(c *Cache) {
+	f(c)
+}
+
+// maxExpiryJitter is the largest jitter fraction, it keeps randomized
+// TTL positive.
+const maxExpiryJitter = 0.99
+
+// WithExpiryJitter randomizes expiry of every item by up to ±fraction
+// of its TTL, so items set with the same TTL don't expire at the same
+// time and cause a reload stampede. Fraction 0.1 expires items with
+// one hour TTL between 54 and 66 minutes. Fraction is clamped to
+// [0, 0.99], so items never expire before they are set.
+func WithExpiryJitter(fraction float64) Option {
+	return OptionFunc(func(c *Cache) {
+		switch {
+		case fraction < 0:
+			fraction = 0
+		case fraction > maxExpiryJitter:
+			fraction = maxExpiryJitter
+		}
+		c.jitter = fraction
+	})
 }
 
 // NewTTL creates a new TTLCache instance and starts a goroutine to periodically
 // remove expired items every 5 seconds.
-func New() *Cache {
+func New(options ...Option) *Cache {
 	c := &Cache{
 		items: make(map[string]item),
 	}
 
+	for _, opt := range options {
+		opt.Apply(c)
+	}
+
 	go func() {
 		for range time.Tick(5 * time.Second) {
 			c.mu.Lock()
@@ -64,11 +104,55 @@ func (c *Cache) Set(key string, value any, ttl time.Duration) error {
 
 	c.items[key] = item{
 		value:  value,
-		expiry: time.Now().Add(ttl),
+		expiry: c.expiry(time.Now(), ttl),
 	}
 	return nil
 }
 
+// expiry returns the expiry time of an item set at now with ttl,
+// randomized by the configured jitter.
+func (c *Cache) expiry(now time.Time, ttl time.Duration) time.Time {
+	if c.jitter > 0 && ttl > 0 {
+		delta := c.jitter * float64(ttl)
+		ttl += time.Duration(delta * (2*rand.Float64() - 1))
+	}
+	return now.Add(ttl)
+}
+
+// SetMany adds all items to the cache with the same time-to-live (TTL)
+// under a single lock acquisition.
+func (c *Cache) SetMany(items map[string]any, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, value := range items {
+		c.items[key] = item{
+			value:  value,
+			expiry: c.expiry(now, ttl),
+		}
+	}
+	return nil
+}
+
+// GetMany retrieves values of the given keys from the cache under a
+// single lock acquisition. Missing and expired keys are omitted from
+// the result.
+func (c *Cache) GetMany(keys ...string) (map[string]any, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	output := make(map[string]any, len(keys))
+	for _, key := range keys {
+		item, found := c.items[key]
+		if !found || item.isExpired() {
+			continue
+		}
+		output[key] = item.value
+	}
+	return output, nil
+}
+
 // Get retrieves the value associated with the given key from the cache.
 func (c *Cache) Get(key string) (any, error) {
 	c.mu.RLock()
@@ -0,0 +1,58 @@
+package inmem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SetManyGetMany(t *testing.T) {
+	c := New()
+
+	err := c.SetMany(map[string]any{
+		"article:1": "first",
+		"article:2": "second",
+	}, time.Minute)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Set("article:3", "expired", -time.Second))
+
+	got, err := c.GetMany("article:1", "article:2", "article:3", "article:4")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"article:1": "first",
+		"article:2": "second",
+	}, got)
+
+	got, err = c.GetMany("article:4")
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestCache_WithExpiryJitter(t *testing.T) {
+	c := New(WithExpiryJitter(0.1))
+
+	before := time.Now()
+	assert.NoError(t, c.Set("article:1", "first", time.Hour))
+	assert.NoError(t, c.Set("article:2", "second", time.Hour))
+	after := time.Now()
+
+	first, second := c.items["article:1"].expiry, c.items["article:2"].expiry
+	assert.NotEqual(t, first, second)
+	for _, expiry := range []time.Time{first, second} {
+		assert.False(t, expiry.Before(before.Add(54*time.Minute)), "expiry %v below jitter bound", expiry)
+		assert.False(t, expiry.After(after.Add(66*time.Minute)), "expiry %v above jitter bound", expiry)
+	}
+
+	c = New()
+	assert.NoError(t, c.SetMany(map[string]any{"a": 1, "b": 2}, time.Hour))
+	assert.Equal(t, c.items["a"].expiry, c.items["b"].expiry)
+
+	assert.Equal(t, 0.0, New(WithExpiryJitter(-0.5)).jitter)
+	c = New(WithExpiryJitter(2))
+	assert.Equal(t, maxExpiryJitter, c.jitter)
+	for i := 0; i < 100; i++ {
+		now := time.Now()
+		assert.True(t, c.expiry(now, time.Hour).After(now), "expiry not after set time")
+	}
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Catalog translates error messages. Messages are looked up by the
+// machine readable code of the error and the locale, msg is the
+// original message which should be returned when there is no
+// translation.
+type Catalog interface {
+	Message(locale, code, msg string) string
+}
+
+// CatalogFunc is an adapter to allow the use of ordinary functions
+// as Catalog.
+type CatalogFunc func(locale, code, msg string) string
+
+// Message calls f(locale, code, msg).
+func (f CatalogFunc) Message(locale, code, msg string) string {
+	return f(locale, code, msg)
+}
+
+// passThrough catalog returns messages unchanged.
+var passThrough = CatalogFunc(func(_, _, msg string) string {
+	return msg
+})
+
+var (
+	catalogMu sync.RWMutex
+	catalog   Catalog = passThrough
+)
+
+// SetCatalog sets the catalog used for translating error messages,
+// nil restores the default pass-through catalog.
+func SetCatalog(c Catalog) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if c == nil {
+		c = passThrough
+	}
+	catalog = c
+}
+
+func getCatalog() Catalog {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return catalog
+}
+
+// MessageFunc returns translated default message of errors with code.
+// Key is the english message format and args are its arguments.
+type MessageFunc func(code Code, key string, args ...any) string
+
+var (
+	messageMu   sync.RWMutex
+	messageFunc MessageFunc
+)
+
+// SetMessageFunc sets the function used to translate default messages
+// of errors created without a message, nil restores english messages.
+func SetMessageFunc(fn MessageFunc) {
+	messageMu.Lock()
+	defer messageMu.Unlock()
+	messageFunc = fn
+}
+
+// message returns default message of errors with code, translated by
+// the function set with SetMessageFunc.
+func message(code Code, key string, args ...any) string {
+	messageMu.RLock()
+	fn := messageFunc
+	messageMu.RUnlock()
+	if fn != nil {
+		return fn(code, key, args...)
+	}
+	if len(args) == 0 {
+		return key
+	}
+	return fmt.Sprintf(key, args...)
+}
+
+type localeKey struct{}
+
+// ContextWithLocale returns a copy of ctx which carries locale.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns locale stored in ctx or empty string.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// Code returns machine readable code of the response. StatusCode is
+// returned when set, otherwise code is derived from http status, for
+// example not_found for http.StatusNotFound.
+func (r HttpResponse) Code() string {
+	if r.StatusCode != "" {
+		return r.StatusCode
+	}
+	return string(CodeFromHttpStatus(r.Status))
+}
+
+// Localize translates response message to locale using the catalog.
+func (r *HttpResponse) Localize(locale string) {
+	r.Msg = getCatalog().Message(locale, r.Code(), r.Msg)
+}
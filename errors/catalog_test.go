@@ -0,0 +1,127 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSONResponseWithLocale(t *testing.T) {
+	SetCatalog(CatalogFunc(func(locale, code, msg string) string {
+		if locale == "de" && code == "not_found" {
+			return "Ressource nicht gefunden"
+		}
+		return msg
+	}))
+	defer SetCatalog(nil)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{
+			name:           "translated",
+			acceptLanguage: "de",
+			want:           "Ressource nicht gefunden",
+		},
+		{
+			name:           "missing translation",
+			acceptLanguage: "fr",
+			want:           "article 123 not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/articles/123", nil)
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+			ctx := ContextWithLocale(r.Context(), r.Header.Get("Accept-Language"))
+
+			w := httptest.NewRecorder()
+			err := JSONResponseCtx(ctx, w, NotFound("article 123 not found"))
+			if err != nil {
+				t.Fatalf("JSONResponse() error = %v", err)
+			}
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("expected status 404, got: %d", w.Code)
+			}
+
+			var resp struct {
+				Msg        string `json:"message"`
+				StatusCode string `json:"status"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			if resp.Msg != tt.want {
+				t.Errorf("expected %q, got: %q", tt.want, resp.Msg)
+			}
+			if resp.StatusCode != "" {
+				t.Errorf("expected empty status code, got: %q", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestSetMessageFunc(t *testing.T) {
+	SetMessageFunc(func(code Code, key string, args ...any) string {
+		if code == CodeNotFound && key == "%v not found" {
+			return fmt.Sprintf("%v nicht gefunden", args...)
+		}
+		return fmt.Sprintf(key, args...)
+	})
+	defer SetMessageFunc(nil)
+
+	if got := (&NotFoundError{Item: "article"}).Error(); got != "article nicht gefunden" {
+		t.Errorf("Error() = %q, want %q", got, "article nicht gefunden")
+	}
+	if got := (&ConflictError{}).Error(); got != "resource already exist" {
+		t.Errorf("Error() = %q, want %q", got, "resource already exist")
+	}
+	if got := NotFound("article %d missing", 1).Error(); got != "article 1 missing" {
+		t.Errorf("Error() = %q, want explicit message unchanged", got)
+	}
+
+	SetMessageFunc(nil)
+	if got := (&NotFoundError{Item: "article"}).Error(); got != "article not found" {
+		t.Errorf("Error() = %q, want %q", got, "article not found")
+	}
+}
+
+func TestResponseCtxLocale(t *testing.T) {
+	SetCatalog(CatalogFunc(func(locale, code, msg string) string {
+		if locale == "de" && code == "not_found" {
+			return "Ressource nicht gefunden"
+		}
+		return msg
+	}))
+	defer SetCatalog(nil)
+
+	ctx := ContextWithLocale(context.Background(), "de")
+
+	w := httptest.NewRecorder()
+	ResponseCtx(ctx, json.NewEncoder(w), w, NotFound("article 123 not found"))
+	var got HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Msg != "Ressource nicht gefunden" {
+		t.Errorf("ResponseCtx() message = %q, want translated", got.Msg)
+	}
+
+	w = httptest.NewRecorder()
+	if err := JSONResponse(w, NotFound("article 123 not found")); err != nil {
+		t.Fatal(err)
+	}
+	got = HttpResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Msg != "article 123 not found" {
+		t.Errorf("JSONResponse() message = %q, want untranslated", got.Msg)
+	}
+}
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// CauseExposure controls how the cause of an InternalError is exposed
+// in http responses.
+type CauseExposure int32
+
+const (
+	// CauseHidden never exposes the cause, this is the default.
+	CauseHidden CauseExposure = iota
+	// CauseRedacted exposes only the type of the cause.
+	CauseRedacted
+	// CauseFull exposes the cause error message.
+	CauseFull
+)
+
+var causeExposure atomic.Int32
+
+// SetCauseExposure sets how the cause of an InternalError is exposed.
+// It should be used only for internal or admin APIs and non production
+// environments, external clients must not see the cause.
+func SetCauseExposure(e CauseExposure) {
+	causeExposure.Store(int32(e))
+}
+
+// cause returns err formatted according to current exposure.
+func cause(err error) string {
+	if err == nil {
+		return ""
+	}
+	switch CauseExposure(causeExposure.Load()) {
+	case CauseRedacted:
+		return fmt.Sprintf("%T", err)
+	case CauseFull:
+		return err.Error()
+	}
+	return ""
+}
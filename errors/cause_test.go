@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInternalErrorCause(t *testing.T) {
+	err := Internal(New("connection refused"), "failed to fetch article")
+
+	tests := []struct {
+		name     string
+		exposure CauseExposure
+		want     string
+	}{
+		{
+			name:     "hidden by default",
+			exposure: CauseHidden,
+			want:     "",
+		},
+		{
+			name:     "redacted",
+			exposure: CauseRedacted,
+			want:     "*errors.errorString",
+		},
+		{
+			name:     "full",
+			exposure: CauseFull,
+			want:     "connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCauseExposure(tt.exposure)
+			defer SetCauseExposure(CauseHidden)
+
+			w := httptest.NewRecorder()
+			if err := JSONResponse(w, err); err != nil {
+				t.Fatal(err)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			cause, _ := got["cause"].(string)
+			if cause != tt.want {
+				t.Errorf("cause = %q, want %q", cause, tt.want)
+			}
+			errs := got["errors"].([]any)
+			if entry := errs[0].(map[string]any); entry["message"] != "Internal Server Error" {
+				t.Errorf("errors = %v, want generic message", errs)
+			}
+		})
+	}
+}
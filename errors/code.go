@@ -0,0 +1,114 @@
+package errors
+
+import "net/http"
+
+// Code is a machine readable error code which stays the same
+// regardless of the message.
+type Code string
+
+const (
+	CodeConflict           Code = "conflict"
+	CodeNotFound           Code = "not_found"
+	CodeInternal           Code = "internal"
+	CodePreconditionFailed Code = "precondition_failed"
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeNotImplemented     Code = "not_implemented"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeDeadlineExceeded   Code = "deadline_exceeded"
+	CodeCanceled           Code = "canceled"
+	CodeAborted            Code = "aborted"
+	CodeTooManyRequests    Code = "too_many_requests"
+)
+
+type coder interface {
+	Code() Code
+}
+
+// AsCode returns the code of the first error in err's tree which
+// provides one.
+func AsCode(err error) (Code, bool) {
+	var c coder
+	if As(err, &c) {
+		return c.Code(), true
+	}
+	return "", false
+}
+
+// CodeFromHttpStatus returns the code matching http status. For
+// statuses without matching code snake case status text is returned.
+func CodeFromHttpStatus(status int) Code {
+	switch status {
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusInternalServerError:
+		return CodeInternal
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CodeInvalidArgument
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodePermissionDenied
+	case http.StatusGatewayTimeout:
+		return CodeDeadlineExceeded
+	case StatusClientClosedRequest:
+		return CodeCanceled
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	}
+	return Code(statusTextCode(status))
+}
+
+// FromCode returns typed error matching code, it is the inverse of
+// AsCode:
+//   - CodeConflict to ConflictError
+//   - CodeAborted to AbortedError
+//   - CodeNotFound to NotFoundError
+//   - CodePreconditionFailed to PreconditionFailedError
+//   - CodeInvalidArgument to ValidationError
+//   - CodeNotImplemented to NotImplementedError
+//   - CodeUnauthenticated to UnauthenticatedError
+//   - CodePermissionDenied to UnauthorizedError
+//   - CodeDeadlineExceeded to TimeoutError
+//   - CodeCanceled to CanceledError
+//   - CodeTooManyRequests to TooManyRequestsError
+//
+// Other codes, including CodeInternal, are returned as InternalError
+// with the code kept as status.
+func FromCode(code Code, format string, args ...any) error {
+	switch code {
+	case CodeConflict:
+		return Conflict(format, args...)
+	case CodeAborted:
+		return Aborted(format, args...)
+	case CodeNotFound:
+		return NotFound(format, args...)
+	case CodePreconditionFailed:
+		return PreconditionFailed(format, args...)
+	case CodeInvalidArgument:
+		return Validation(format, args...)
+	case CodeNotImplemented:
+		return NotImplemented(format, args...)
+	case CodeUnauthenticated:
+		return Unauthenticated(format, args...)
+	case CodePermissionDenied:
+		return Unauthorized(format, args...)
+	case CodeDeadlineExceeded:
+		return Timeout(format, args...)
+	case CodeCanceled:
+		return Canceled(format, args...)
+	case CodeTooManyRequests:
+		return TooManyRequests(format, args...)
+	case CodeInternal:
+		return Internal(nil, format, args...)
+	}
+	err := Internal(nil, format, args...)
+	err.StatusCode = string(code)
+	return err
+}
@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAsCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   Code
+		wantOk bool
+	}{
+		{
+			name:   "conflict",
+			err:    Conflict("article 123 already exist"),
+			want:   CodeConflict,
+			wantOk: true,
+		},
+		{
+			name:   "wrapped not found",
+			err:    fmt.Errorf("get article: %w", NotFound("article 123 not found")),
+			want:   CodeNotFound,
+			wantOk: true,
+		},
+		{
+			name:   "internal",
+			err:    Internal(New("fatal error"), "merge failed"),
+			want:   CodeInternal,
+			wantOk: true,
+		},
+		{
+			name:   "precondition failed",
+			err:    PreconditionFailed("unable to commit"),
+			want:   CodePreconditionFailed,
+			wantOk: true,
+		},
+		{
+			name:   "validation",
+			err:    Validation("name is mandatory field"),
+			want:   CodeInvalidArgument,
+			wantOk: true,
+		},
+		{
+			name:   "not implemented",
+			err:    NotImplemented("operation not implemented"),
+			want:   CodeNotImplemented,
+			wantOk: true,
+		},
+		{
+			name:   "unauthenticated",
+			err:    Unauthenticated("missing token"),
+			want:   CodeUnauthenticated,
+			wantOk: true,
+		},
+		{
+			name:   "unauthorized",
+			err:    Unauthorized("access denied"),
+			want:   CodePermissionDenied,
+			wantOk: true,
+		},
+		{
+			name: "std error",
+			err:  New("plain error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := AsCode(tt.err)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("AsCode() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCodeFromHttpStatus(t *testing.T) {
+	errs := []error{
+		Conflict(""), NotFound(""), Internal(nil, ""), PreconditionFailed(""),
+		Validation(""), NotImplemented(""), Unauthenticated(""), Unauthorized(""),
+	}
+	for _, err := range errs {
+		code, _ := AsCode(err)
+		if got := CodeFromHttpStatus(HttpStatus(err)); got != code {
+			t.Errorf("CodeFromHttpStatus() = %v, want %v", got, code)
+		}
+	}
+
+	if got := CodeFromHttpStatus(http.StatusTooManyRequests); got != "too_many_requests" {
+		t.Errorf("CodeFromHttpStatus() = %v, want too_many_requests", got)
+	}
+}
+
+func TestFromCode(t *testing.T) {
+	codes := []Code{
+		CodeConflict, CodeAborted, CodeNotFound, CodeInternal, CodePreconditionFailed,
+		CodeInvalidArgument, CodeNotImplemented, CodeUnauthenticated, CodePermissionDenied,
+		CodeDeadlineExceeded, CodeCanceled, CodeTooManyRequests,
+	}
+	for _, code := range codes {
+		t.Run(string(code), func(t *testing.T) {
+			err := FromCode(code, "operation %s failed", "x")
+			if got, _ := AsCode(err); got != code {
+				t.Errorf("AsCode() = %v, want %v", got, code)
+			}
+			if err.Error() != "operation x failed" {
+				t.Errorf("Error() = %q, want %q", err.Error(), "operation x failed")
+			}
+
+			w := httptest.NewRecorder()
+			if err := JSONResponse(w, err); err != nil {
+				t.Fatal(err)
+			}
+			var response HttpResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			response.Status = w.Code
+
+			got := response.AsError()
+			if gotCode, _ := AsCode(got); gotCode != code {
+				t.Errorf("AsError() code = %v, want %v", gotCode, code)
+			}
+			if got.Error() != err.Error() {
+				t.Errorf("AsError() = %q, want %q", got.Error(), err.Error())
+			}
+			if HttpStatus(got) != HttpStatus(err) {
+				t.Errorf("AsError() status = %d, want %d", HttpStatus(got), HttpStatus(err))
+			}
+			if err := JSONResponse(httptest.NewRecorder(), got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	err := FromCode("quota_exceeded", "quota exceeded")
+	if _, ok := AsInternal(err); !ok || DetailTree(err).Code != "quota_exceeded" {
+		t.Errorf("FromCode() = %v, want InternalError with quota_exceeded status", err)
+	}
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// multiError combines errors, its http status and response are the
+// ones of the most severe member.
+type multiError struct {
+	errs []error
+}
+
+// Combine returns an error that wraps errs, nil errors are discarded.
+// Combine returns nil if all errs are nil and the error itself if
+// only one is not nil. Members are reachable with Is and As, http
+// status and response of the combined error are the ones of the most
+// severe member in order:
+//  1. 5xx server errors
+//  2. 409 conflict
+//  3. 412 precondition failed
+//  4. 429 too many requests
+//  5. 400 bad request and 422 unprocessable entity
+//  6. 403 forbidden
+//  7. 401 unauthorized
+//  8. 404 not found
+//  9. other statuses
+//
+// Members with the same severity are resolved in favour of the first
+// one.
+func Combine(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+	return &multiError{errs: nonNil}
+}
+
+func (e *multiError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns combined errors.
+func (e *multiError) Unwrap() []error {
+	return e.errs
+}
+
+// HttpStatus returns http status code of the most severe member.
+func (e *multiError) HttpStatus() int {
+	return HttpStatus(e.mostSevere())
+}
+
+// HttpResponse returns http response of the most severe member.
+func (e *multiError) HttpResponse() HttpResponse {
+	err := e.mostSevere()
+	var v httpResponse
+	if As(err, &v) {
+		return v.HttpResponse()
+	}
+	return HttpResponse{
+		Base:   NewBase(err.Error()),
+		Status: http.StatusInternalServerError,
+	}
+}
+
+func (e *multiError) mostSevere() error {
+	most := e.errs[0]
+	for _, err := range e.errs[1:] {
+		if severity(HttpStatus(err)) > severity(HttpStatus(most)) {
+			most = err
+		}
+	}
+	return most
+}
+
+// severity ranks http status, see Combine for the order.
+func severity(status int) int {
+	switch {
+	case status >= 500:
+		return 8
+	case status == http.StatusConflict:
+		return 7
+	case status == http.StatusPreconditionFailed:
+		return 6
+	case status == http.StatusTooManyRequests:
+		return 5
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return 4
+	case status == http.StatusForbidden:
+		return 3
+	case status == http.StatusUnauthorized:
+		return 2
+	case status == http.StatusNotFound:
+		return 1
+	}
+	return 0
+}
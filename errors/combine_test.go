@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	if err := Combine(nil, nil); err != nil {
+		t.Errorf("Combine(nil, nil) = %v, want nil", err)
+	}
+	notFound := NotFound("article not found")
+	if err := Combine(nil, notFound); err != notFound {
+		t.Errorf("Combine() = %v, want %v", err, notFound)
+	}
+
+	tests := []struct {
+		name string
+		errs []error
+		want int
+	}{
+		{
+			name: "validation over not found",
+			errs: []error{NotFound("article not found"), Validation("invalid title")},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "conflict over validation",
+			errs: []error{Validation("invalid title"), Conflict("article already exist")},
+			want: http.StatusConflict,
+		},
+		{
+			name: "server error over conflict",
+			errs: []error{Conflict("article already exist"), New("connection refused")},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "first of same severity",
+			errs: []error{Unauthenticated("token expired"), NotFound("article not found")},
+			want: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Combine(tt.errs...)
+			if got := HttpStatus(err); got != tt.want {
+				t.Errorf("HttpStatus() = %d, want %d", got, tt.want)
+			}
+
+			w := httptest.NewRecorder()
+			if err := JSONResponse(w, err); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.want {
+				t.Errorf("JSONResponse() status = %d, want %d", w.Code, tt.want)
+			}
+
+			for _, member := range tt.errs {
+				if !Is(err, member) {
+					t.Errorf("Is(%v) = false, want true", member)
+				}
+			}
+		})
+	}
+
+	err := Combine(NotFound("article not found"), Conflict("article ${%d} already exist", 1))
+	if !IsNotFound(err) || !IsConflict(err) {
+		t.Errorf("Combine() members not reachable with Is")
+	}
+	if cerr, ok := AsConflict(err); !ok || cerr.Item != 1 {
+		t.Errorf("AsConflict() = %v, %v", cerr, ok)
+	}
+}
@@ -1,12 +1,10 @@
 package errors
 
 import (
-	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"runtime/debug"
 	"strings"
 	"time"
 )
@@ -54,7 +52,9 @@ func TraceID(id string, t error) error {
 type ConflictError struct {
 	Base
 	// Item is a conflicting resource.
-	Item   any               `json:"item,omitempty"`
+	Item any `json:"item,omitempty"`
+	// Field is a field or constraint name which caused the conflict.
+	Field  string            `json:"field,omitempty"`
 	Errors MarshalableErrors `json:"errors"`
 }
 
@@ -75,6 +75,15 @@ func Conflict(format string, args ...any) *ConflictError {
 	}
 }
 
+// ConflictOnField is a helper function to return an ConflictError
+// for the field or constraint, for example:
+//   - errors.ConflictOnField("email", "user ${%s} already exist", email)
+func ConflictOnField(field string, format string, args ...any) *ConflictError {
+	err := Conflict(format, args...)
+	err.Field = field
+	return err
+}
+
 // IsConflict checks if err is conflict error.
 func IsConflict(err error) bool {
 	return errors.Is(err, &ConflictError{})
@@ -96,24 +105,54 @@ func (e *ConflictError) Error() string {
 		return e.Msg
 	}
 	if e.Item != nil {
-		return fmt.Sprintf("%v already exist", e.Item)
+		return message(e.Code(), "%v already exist", e.Item)
 	}
-	return "resource already exist"
+	return message(e.Code(), "resource already exist")
+}
+
+// Code returns error code for ConflictError.
+func (e *ConflictError) Code() Code {
+	return CodeConflict
+}
+
+// HttpStatus returns http status code for ConflictError.
+func (e *ConflictError) HttpStatus() int {
+	return http.StatusConflict
 }
 
 // HttpResponse returns http response for ConflictError.
 func (e *ConflictError) HttpResponse() HttpResponse {
-	slice := make([]string, len(e.Errors))
-	for i, e := range e.Errors {
-		slice[i] = e.Error()
-	}
 	return HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusConflict,
-		Errors: slice,
+		Status: e.HttpStatus(),
+		Item:   e.Item,
+		Field:  e.Field,
+		Errors: fieldErrors(e.Errors),
 	}
 }
 
+// ConflictItem returns Item of the ConflictError in err's tree as T.
+// Items decoded from http responses are converted to T through JSON,
+// so numbers and structs survive the round trip.
+func ConflictItem[T any](err error) (T, bool) {
+	var item T
+	cerr, ok := AsConflict(err)
+	if !ok || cerr.Item == nil {
+		return item, false
+	}
+	if v, ok := cerr.Item.(T); ok {
+		return v, true
+	}
+	data, jerr := json.Marshal(cerr.Item)
+	if jerr != nil {
+		return item, false
+	}
+	if jerr := json.Unmarshal(data, &item); jerr != nil {
+		return item, false
+	}
+	return item, true
+}
+
 func (e *ConflictError) AddError(err error) *ConflictError {
 	if err != nil {
 		e.Errors = append(e.Errors, err)
@@ -127,6 +166,72 @@ func (e *ConflictError) Is(t error) bool {
 	return ok
 }
 
+// AbortedError is returned when operation was aborted because of
+// concurrency issue, like transaction conflict or deadlock, and can be
+// retried.
+type AbortedError struct {
+	Base
+}
+
+// Aborted is a helper function to return an AbortedError.
+func Aborted(format string, args ...any) *AbortedError {
+	return &AbortedError{
+		Base: NewBase(format, args...),
+	}
+}
+
+// IsAborted checks if err is aborted error.
+func IsAborted(err error) bool {
+	return errors.Is(err, &AbortedError{})
+}
+
+// AsAborted return err as AbortedError or nil if it is not
+// successfull.
+func AsAborted(err error) (aerr *AbortedError, b bool) {
+	if errors.As(err, &aerr) {
+		return aerr, true
+	}
+
+	return nil, false
+}
+
+func (e *AbortedError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return message(e.Code(), "operation aborted")
+}
+
+// Code returns error code for AbortedError.
+func (e *AbortedError) Code() Code {
+	return CodeAborted
+}
+
+// HttpStatus returns http status code for AbortedError.
+func (e *AbortedError) HttpStatus() int {
+	return http.StatusConflict
+}
+
+// HttpResponse returns http response for AbortedError. Status is set
+// to aborted code when empty, so the response can be told apart from
+// ConflictError.
+func (e *AbortedError) HttpResponse() HttpResponse {
+	base := e.Base
+	if base.StatusCode == "" {
+		base.StatusCode = string(CodeAborted)
+	}
+	return HttpResponse{
+		Base:   base,
+		Status: e.HttpStatus(),
+	}
+}
+
+// Is checks if err is AbortedError.
+func (e *AbortedError) Is(err error) bool {
+	_, ok := err.(*AbortedError)
+	return ok
+}
+
 type NotFoundError struct {
 	Base
 	Item any `json:"item,omitempty"`
@@ -169,16 +274,26 @@ func (e *NotFoundError) Error() string {
 		return e.Msg
 	}
 	if e.Item != nil {
-		return fmt.Sprintf("%v not found", e.Item)
+		return message(e.Code(), "%v not found", e.Item)
 	}
-	return "resource not found"
+	return message(e.Code(), "resource not found")
+}
+
+// Code returns error code for NotFoundError.
+func (e *NotFoundError) Code() Code {
+	return CodeNotFound
+}
+
+// HttpStatus returns http status code for NotFoundError.
+func (e *NotFoundError) HttpStatus() int {
+	return http.StatusNotFound
 }
 
 // HttpResponse returns http response for NotFoundError.
 func (e *NotFoundError) HttpResponse() HttpResponse {
 	return HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusNotFound,
+		Status: e.HttpStatus(),
 	}
 }
 
@@ -189,8 +304,8 @@ func (e *NotFoundError) Is(err error) bool {
 
 type InternalError struct {
 	Base
-	Err        error           `json:"-"`
-	Stacktrace json.RawMessage `json:"-"`
+	Err        error   `json:"-"`
+	Stacktrace []Frame `json:"-"`
 }
 
 // Internal is a helper function to return an internal Error.
@@ -198,7 +313,7 @@ func Internal(err error, format string, args ...any) *InternalError {
 	return &InternalError{
 		Base:       NewBase(format, args...),
 		Err:        err,
-		Stacktrace: debug.Stack(),
+		Stacktrace: callers(1),
 	}
 }
 
@@ -220,17 +335,34 @@ func (e *InternalError) Error() string {
 		return e.Msg
 	}
 	if e.Err != nil {
-		return fmt.Sprintf("internal server error: %s", e.Err)
+		return message(e.Code(), "internal server error: %s", e.Err)
 	}
-	return "internal server error"
+	return message(e.Code(), "internal server error")
+}
+
+// Code returns error code for InternalError.
+func (e *InternalError) Code() Code {
+	return CodeInternal
+}
+
+// Frames returns stack frames captured when the error was created.
+func (e *InternalError) Frames() []Frame {
+	return e.Stacktrace
+}
+
+// HttpStatus returns http status code for InternalError.
+func (e *InternalError) HttpStatus() int {
+	return http.StatusInternalServerError
 }
 
 // HttpResponse returns http response for InternalError.
 func (e *InternalError) HttpResponse() HttpResponse {
 	return HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusInternalServerError,
-		Errors: []string{cmp.Or(e.Err, New("Internal Server Error")).Error()},
+		Status: e.HttpStatus(),
+		Cause:  cause(e.Err),
+		Stack:  debugStack(e.Stacktrace),
+		Errors: []FieldError{{Message: "Internal Server Error"}},
 	}
 }
 
@@ -255,16 +387,29 @@ func (e *PreconditionFailedError) Error() string {
 	if e.Msg != "" {
 		return e.Msg
 	}
-	return "precondition failed error"
+	return message(e.Code(), "precondition failed error")
+}
+
+// Code returns error code for PreconditionFailedError.
+func (e *PreconditionFailedError) Code() Code {
+	return CodePreconditionFailed
+}
+
+// HttpStatus returns http status code for PreconditionFailedError.
+func (e *PreconditionFailedError) HttpStatus() int {
+	return http.StatusPreconditionFailed
 }
 
 // HttpResponse returns http response for PreconditionFailedError.
 func (e *PreconditionFailedError) HttpResponse() HttpResponse {
-	return HttpResponse{
+	response := HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusPreconditionFailed,
-		Errors: []string{e.Err.Error()},
+		Status: e.HttpStatus(),
+	}
+	if e.Err != nil {
+		response.Errors = []FieldError{{Message: e.Err.Error()}}
 	}
+	return response
 }
 
 func (e *PreconditionFailedError) SetErr(err error) *PreconditionFailedError {
@@ -293,8 +438,62 @@ func (me MarshalableErrors) MarshalJSON() ([]byte, error) {
 	return data, nil
 }
 
+// FieldError is a single entry of the errors array in http response.
+// Field is empty for errors which are not related to any field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// UnmarshalJSON accepts plain strings too, as written by services
+// before errors were structured.
+func (e *FieldError) UnmarshalJSON(data []byte) error {
+	var msg string
+	if err := json.Unmarshal(data, &msg); err == nil {
+		*e = FieldError{Message: msg}
+		return nil
+	}
+	type plain FieldError
+	return json.Unmarshal(data, (*plain)(e))
+}
+
+// fieldErrors converts errs to response entries, errors other than
+// FieldError are folded into entries with an empty Field.
+func fieldErrors(errs []error) []FieldError {
+	entries := make([]FieldError, len(errs))
+	for i, err := range errs {
+		var ferr *FieldError
+		if errors.As(err, &ferr) {
+			entries[i] = *ferr
+			continue
+		}
+		entries[i] = FieldError{Message: err.Error()}
+	}
+	return entries
+}
+
+// ValidationKind distinguishes malformed requests from well formed
+// requests with semantically invalid content.
+type ValidationKind int
+
+const (
+	// KindMalformed is malformed request, http status 400.
+	KindMalformed ValidationKind = iota
+	// KindUnprocessable is semantically invalid content, http status 422.
+	KindUnprocessable
+)
+
 type ValidationError struct {
 	Base
+	Kind   ValidationKind    `json:"-"`
 	Errors MarshalableErrors `json:"errors,omitempty"`
 }
 
@@ -305,6 +504,15 @@ func Validation(format string, args ...any) *ValidationError {
 	}
 }
 
+// Unprocessable is a helper function to return an invalid argument
+// Error for semantically invalid content (422).
+func Unprocessable(format string, args ...any) *ValidationError {
+	return &ValidationError{
+		Base: NewBase(format, args...),
+		Kind: KindUnprocessable,
+	}
+}
+
 // IsValidation checks if err is invalid argument error.
 func IsValidation(err error) bool {
 	return errors.Is(err, &ValidationError{})
@@ -322,19 +530,28 @@ func (e *ValidationError) Error() string {
 	if e.Msg != "" {
 		return e.Msg
 	}
-	return "validation error"
+	return message(e.Code(), "validation error")
+}
+
+// Code returns error code for ValidationError.
+func (e *ValidationError) Code() Code {
+	return CodeInvalidArgument
+}
+
+// HttpStatus returns http status code for ValidationError.
+func (e *ValidationError) HttpStatus() int {
+	if e.Kind == KindUnprocessable {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
 }
 
 // HttpResponse returns http response for ValidationError.
 func (e *ValidationError) HttpResponse() HttpResponse {
-	slice := make([]string, len(e.Errors))
-	for i, e := range e.Errors {
-		slice[i] = e.Error()
-	}
 	return HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusBadRequest,
-		Errors: slice,
+		Status: e.HttpStatus(),
+		Errors: fieldErrors(e.Errors),
 	}
 }
 
@@ -345,6 +562,11 @@ func (e *ValidationError) AddError(err error) *ValidationError {
 	return e
 }
 
+// AddFieldError records msg as an error of the field.
+func (e *ValidationError) AddFieldError(field, msg string) *ValidationError {
+	return e.AddError(&FieldError{Field: field, Message: msg})
+}
+
 func (e *ValidationError) Is(err error) bool {
 	_, ok := err.(*ValidationError)
 	return ok
@@ -370,14 +592,24 @@ func (e *NotImplementedError) Error() string {
 	if e.Msg != "" {
 		return e.Msg
 	}
-	return "operation not implemented"
+	return message(e.Code(), "operation not implemented")
+}
+
+// Code returns error code for NotImplementedError.
+func (e *NotImplementedError) Code() Code {
+	return CodeNotImplemented
+}
+
+// HttpStatus returns http status code for NotImplementedError.
+func (e *NotImplementedError) HttpStatus() int {
+	return http.StatusNotImplemented
 }
 
 // HttpResponse returns http response for NotImplementedError.
 func (e *NotImplementedError) HttpResponse() HttpResponse {
 	return HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusNotImplemented,
+		Status: e.HttpStatus(),
 	}
 }
 
@@ -399,14 +631,24 @@ func (e *UnauthenticatedError) Error() string {
 	if e.Msg != "" {
 		return e.Msg
 	}
-	return "unauthenticated"
+	return message(e.Code(), "unauthenticated")
+}
+
+// Code returns error code for UnauthenticatedError.
+func (e *UnauthenticatedError) Code() Code {
+	return CodeUnauthenticated
+}
+
+// HttpStatus returns http status code for UnauthenticatedError.
+func (e *UnauthenticatedError) HttpStatus() int {
+	return http.StatusUnauthorized
 }
 
 // HttpResponse returns http response for UnauthenticatedError.
 func (e *UnauthenticatedError) HttpResponse() HttpResponse {
 	return HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusUnauthorized,
+		Status: e.HttpStatus(),
 	}
 }
 
@@ -428,13 +670,91 @@ func (e *UnauthorizedError) Error() string {
 	if e.Msg != "" {
 		return e.Msg
 	}
-	return "unauthorized"
+	return message(e.Code(), "unauthorized")
+}
+
+// Code returns error code for UnauthorizedError.
+func (e *UnauthorizedError) Code() Code {
+	return CodePermissionDenied
+}
+
+// HttpStatus returns http status code for UnauthorizedError.
+func (e *UnauthorizedError) HttpStatus() int {
+	return http.StatusForbidden
 }
 
 // HttpResponse returns http response for UnauthorizedError.
 func (e *UnauthorizedError) HttpResponse() HttpResponse {
 	return HttpResponse{
 		Base:   e.Base,
-		Status: http.StatusForbidden,
+		Status: e.HttpStatus(),
+	}
+}
+
+// TooManyRequestsError is returned when client exceeded the rate limit.
+// RetryAfter is the time client should wait before the next request.
+type TooManyRequestsError struct {
+	Base
+	RetryAfter time.Duration `json:"-"`
+}
+
+// TooManyRequests is a helper function to return a TooManyRequestsError.
+func TooManyRequests(format string, args ...any) *TooManyRequestsError {
+	return &TooManyRequestsError{
+		Base: NewBase(format, args...),
+	}
+}
+
+// IsTooManyRequests checks if err is too many requests error.
+func IsTooManyRequests(err error) bool {
+	return errors.Is(err, &TooManyRequestsError{})
+}
+
+// AsTooManyRequests return err as TooManyRequestsError or nil if it is
+// not successfull.
+func AsTooManyRequests(err error) (terr *TooManyRequestsError, b bool) {
+	if errors.As(err, &terr) {
+		return terr, true
+	}
+
+	return nil, false
+}
+
+func (e *TooManyRequestsError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
 	}
+	return message(e.Code(), "too many requests")
+}
+
+// Code returns error code for TooManyRequestsError.
+func (e *TooManyRequestsError) Code() Code {
+	return CodeTooManyRequests
+}
+
+// HttpStatus returns http status code for TooManyRequestsError.
+func (e *TooManyRequestsError) HttpStatus() int {
+	return http.StatusTooManyRequests
+}
+
+// HttpResponse returns http response for TooManyRequestsError.
+// RetryAfter is rounded up to whole seconds.
+func (e *TooManyRequestsError) HttpResponse() HttpResponse {
+	return HttpResponse{
+		Base:       e.Base,
+		Status:     e.HttpStatus(),
+		RetryAfter: int((e.RetryAfter + time.Second - 1) / time.Second),
+	}
+}
+
+// SetRetryAfter sets the time client should wait before retrying.
+func (e *TooManyRequestsError) SetRetryAfter(d time.Duration) *TooManyRequestsError {
+	e.RetryAfter = d
+	return e
+}
+
+// Is checks if err is TooManyRequestsError.
+func (e *TooManyRequestsError) Is(err error) bool {
+	_, ok := err.(*TooManyRequestsError)
+	return ok
 }
@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestConflict(t *testing.T) {
@@ -53,6 +56,61 @@ func TestConflictItem(t *testing.T) {
 	}
 }
 
+func TestConflictItemRoundTrip(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := JSONResponse(w, &ConflictError{Item: 123}); err != nil {
+		t.Fatal(err)
+	}
+
+	var response HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	response.Status = w.Code
+
+	err := fmt.Errorf("create article: %w", response.AsError())
+	if v, ok := ConflictItem[int](err); !ok || v != 123 {
+		t.Errorf("ConflictItem() = %v, %v, want 123, true", v, ok)
+	}
+	if _, ok := ConflictItem[string](err); ok {
+		t.Errorf("ConflictItem[string]() = true, want false")
+	}
+	if _, ok := ConflictItem[int](NotFound("article not found")); ok {
+		t.Errorf("ConflictItem() on NotFoundError = true, want false")
+	}
+}
+
+func TestConflictOnField(t *testing.T) {
+	err := ConflictOnField("email", "user ${%s} already exist", "john@example.com")
+
+	c, ok := AsConflict(err)
+	if !ok {
+		t.Fatalf("expected ConflictError, got: %T", err)
+	}
+
+	if c.Field != "email" {
+		t.Errorf("expected field email, got: %s", c.Field)
+	}
+
+	if c.Item != "john@example.com" {
+		t.Errorf("expected item john@example.com, got: %v", c.Item)
+	}
+
+	w := httptest.NewRecorder()
+	if err := JSONResponse(w, err); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+
+	var resp map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+
+	if resp["field"] != "email" {
+		t.Errorf("expected field email in response, got: %v", resp["field"])
+	}
+}
+
 type mergeConflictError struct {
 	ConflictError
 	Base  string   `json:"base"`
@@ -281,7 +339,7 @@ func TestInternalError_HttpStatus(t *testing.T) {
 	type fields struct {
 		Msg        string
 		Err        error
-		Stacktrace []byte
+		Stacktrace []Frame
 	}
 	tests := []struct {
 		name   string
@@ -306,6 +364,28 @@ func TestInternalError_HttpStatus(t *testing.T) {
 	}
 }
 
+func TestPreconditionFailedJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := JSONResponse(w, PreconditionFailed("etag mismatch")); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+
+	w = httptest.NewRecorder()
+	if err := JSONResponse(w, PreconditionFailed("etag mismatch").SetErr(New("version 3 != 4"))); err != nil {
+		t.Fatal(err)
+	}
+	var response HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if want := []FieldError{{Message: "version 3 != 4"}}; !reflect.DeepEqual(response.Errors, want) {
+		t.Errorf("Errors = %v, want %v", response.Errors, want)
+	}
+}
+
 func TestPreconditionFailedError_HttpStatus(t *testing.T) {
 	type fields struct {
 		Msg string
@@ -434,3 +514,124 @@ func TestUnauthorizedError_HttpStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationError_AddFieldError(t *testing.T) {
+	err := Validation("article validation error").
+		AddFieldError("title", "is required").
+		AddError(&FieldError{Field: "status", Message: "must be draft or published", Code: "one_of"}).
+		AddError(errors.New("body is too long"))
+
+	w := httptest.NewRecorder()
+	if err := JSONResponse(w, err); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Errors []map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]string{
+		{"field": "title", "message": "is required"},
+		{"field": "status", "message": "must be draft or published", "code": "one_of"},
+		{"field": "", "message": "body is too long"},
+	}
+	if !reflect.DeepEqual(got.Errors, want) {
+		t.Errorf("errors = %v, want %v", got.Errors, want)
+	}
+}
+
+func TestFieldError_UnmarshalJSON(t *testing.T) {
+	var response HttpResponse
+	if err := json.Unmarshal([]byte(`{"errors":["title is required",{"field":"body","message":"too long"}]}`), &response); err != nil {
+		t.Fatal(err)
+	}
+	want := []FieldError{
+		{Message: "title is required"},
+		{Field: "body", Message: "too long"},
+	}
+	if !reflect.DeepEqual(response.Errors, want) {
+		t.Errorf("Errors = %v, want %v", response.Errors, want)
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	err := TooManyRequests("rate limit exceeded").SetRetryAfter(1500 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	if err := JSONResponse(w, err); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want %q", got, "2")
+	}
+
+	var response HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	response.Status = w.Code
+
+	terr, ok := AsTooManyRequests(response.AsError())
+	if !ok {
+		t.Fatalf("AsError() = %v, want TooManyRequestsError", response.AsError())
+	}
+	if terr.RetryAfter != 2*time.Second {
+		t.Errorf("RetryAfter = %v, want %v", terr.RetryAfter, 2*time.Second)
+	}
+}
+
+func TestHttpResponse_AsError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "malformed", err: Validation("invalid json").AddError(New("unexpected EOF"))},
+		{name: "unprocessable", err: Unprocessable("invalid article").AddError(New("title: required"))},
+		{name: "field errors", err: Validation("invalid article").AddFieldError("title", "required").AddError(New("too many fields"))},
+		{name: "conflict", err: ConflictOnField("email", "user already exist")},
+		{name: "aborted", err: Aborted("transaction aborted, please retry")},
+		{name: "too many requests", err: TooManyRequests("rate limit exceeded").SetRetryAfter(time.Minute)},
+		{name: "not found", err: NotFound("article not found")},
+		{name: "unauthenticated", err: Unauthenticated("token expired")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := JSONResponse(w, tt.err); err != nil {
+				t.Fatal(err)
+			}
+
+			var response HttpResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			response.Status = w.Code
+
+			got := response.AsError()
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.err) {
+				t.Errorf("AsError() = %T, want %T", got, tt.err)
+			}
+			if HttpStatus(got) != HttpStatus(tt.err) {
+				t.Errorf("HttpStatus() = %v, want %v", HttpStatus(got), HttpStatus(tt.err))
+			}
+			if got.Error() != tt.err.Error() {
+				t.Errorf("Error() = %q, want %q", got.Error(), tt.err.Error())
+			}
+			if !reflect.DeepEqual(got.(httpResponse).HttpResponse().Errors, tt.err.(httpResponse).HttpResponse().Errors) {
+				t.Errorf("Errors = %v, want %v", got.(httpResponse).HttpResponse().Errors,
+					tt.err.(httpResponse).HttpResponse().Errors)
+			}
+		})
+	}
+
+	verr, ok := AsValidation(HttpResponse{Status: http.StatusUnprocessableEntity}.AsError())
+	if !ok || verr.Kind != KindUnprocessable {
+		t.Errorf("AsError() = %v, want unprocessable ValidationError", verr)
+	}
+}
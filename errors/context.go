@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the
+// client closed the connection before the response was written.
+const StatusClientClosedRequest = 499
+
+// TimeoutError is returned when the request deadline is exceeded.
+type TimeoutError struct {
+	Base
+	Err error `json:"-"`
+}
+
+// Timeout is a helper function to return a TimeoutError.
+func Timeout(format string, args ...any) *TimeoutError {
+	return &TimeoutError{
+		Base: NewBase(format, args...),
+		Err:  context.DeadlineExceeded,
+	}
+}
+
+// IsTimeout checks if err is timeout error.
+func IsTimeout(err error) bool {
+	return errors.Is(err, &TimeoutError{})
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return message(e.Code(), "request timed out")
+}
+
+// Unwrap returns the context error.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns error code for TimeoutError.
+func (e *TimeoutError) Code() Code {
+	return CodeDeadlineExceeded
+}
+
+// HttpStatus returns http status code for TimeoutError.
+func (e *TimeoutError) HttpStatus() int {
+	return http.StatusGatewayTimeout
+}
+
+// HttpResponse returns http response for TimeoutError.
+func (e *TimeoutError) HttpResponse() HttpResponse {
+	return HttpResponse{
+		Base:   e.Base,
+		Status: e.HttpStatus(),
+	}
+}
+
+func (e *TimeoutError) Is(err error) bool {
+	_, ok := err.(*TimeoutError)
+	return ok
+}
+
+// CanceledError is returned when the client canceled the request.
+type CanceledError struct {
+	Base
+	Err error `json:"-"`
+}
+
+// Canceled is a helper function to return a CanceledError.
+func Canceled(format string, args ...any) *CanceledError {
+	return &CanceledError{
+		Base: NewBase(format, args...),
+		Err:  context.Canceled,
+	}
+}
+
+// IsCanceled checks if err is canceled error.
+func IsCanceled(err error) bool {
+	return errors.Is(err, &CanceledError{})
+}
+
+func (e *CanceledError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return message(e.Code(), "request canceled")
+}
+
+// Unwrap returns the context error.
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns error code for CanceledError.
+func (e *CanceledError) Code() Code {
+	return CodeCanceled
+}
+
+// HttpStatus returns http status code for CanceledError.
+func (e *CanceledError) HttpStatus() int {
+	return StatusClientClosedRequest
+}
+
+// HttpResponse returns http response for CanceledError.
+func (e *CanceledError) HttpResponse() HttpResponse {
+	return HttpResponse{
+		Base:   e.Base,
+		Status: e.HttpStatus(),
+	}
+}
+
+func (e *CanceledError) Is(err error) bool {
+	_, ok := err.(*CanceledError)
+	return ok
+}
+
+// FromContext returns TimeoutError for context.DeadlineExceeded and
+// CanceledError for context.Canceled in err's tree. Errors which
+// already provide http status, and other errors, are returned
+// unchanged.
+func FromContext(err error) error {
+	var status httpStatus
+	if err == nil || As(err, &status) {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &TimeoutError{Base: NewBase("request timed out"), Err: err}
+	case errors.Is(err, context.Canceled):
+		return &CanceledError{Base: NewBase("request canceled"), Err: err}
+	}
+	return err
+}
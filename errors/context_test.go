@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   Code
+	}{
+		{
+			name:   "deadline exceeded",
+			err:    fmt.Errorf("query articles: %w", context.DeadlineExceeded),
+			status: http.StatusGatewayTimeout,
+			code:   CodeDeadlineExceeded,
+		},
+		{
+			name:   "canceled",
+			err:    fmt.Errorf("query articles: %w", context.Canceled),
+			status: StatusClientClosedRequest,
+			code:   CodeCanceled,
+		},
+		{
+			name:   "typed error wrapping context error",
+			err:    Internal(context.DeadlineExceeded, "merge failed"),
+			status: http.StatusInternalServerError,
+			code:   CodeInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HttpStatus(tt.err); got != tt.status {
+				t.Errorf("HttpStatus() = %d, want %d", got, tt.status)
+			}
+			if code, _ := AsCode(FromContext(tt.err)); code != tt.code {
+				t.Errorf("AsCode() = %s, want %s", code, tt.code)
+			}
+
+			w := httptest.NewRecorder()
+			if err := JSONResponse(w, tt.err); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.status {
+				t.Errorf("JSONResponse() status = %d, want %d", w.Code, tt.status)
+			}
+
+			w = httptest.NewRecorder()
+			Response(json.NewEncoder(w), w, tt.err)
+			if w.Code != tt.status {
+				t.Errorf("Response() status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+
+	if err := FromContext(context.DeadlineExceeded); !IsTimeout(err) || !Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected TimeoutError wrapping context error, got: %v", err)
+	}
+	if err := FromContext(context.Canceled); !IsCanceled(err) || !Is(err, context.Canceled) {
+		t.Errorf("expected CanceledError wrapping context error, got: %v", err)
+	}
+	plain := New("plain")
+	if err := FromContext(plain); err != plain {
+		t.Errorf("FromContext() = %v, want unchanged error", err)
+	}
+}
+
+func TestHttpResponse_AsError_Context(t *testing.T) {
+	if err := (HttpResponse{Status: http.StatusGatewayTimeout}).AsError(); !IsTimeout(err) {
+		t.Errorf("expected TimeoutError, got: %T", err)
+	}
+	if err := (HttpResponse{Status: StatusClientClosedRequest}).AsError(); !IsCanceled(err) {
+		t.Errorf("expected CanceledError, got: %T", err)
+	}
+}
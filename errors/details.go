@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ErrorNode is a structured representation of an error and the errors
+// it contains.
+type ErrorNode struct {
+	// Code is a machine readable code of typed errors.
+	Code     string      `json:"code,omitempty"`
+	Message  string      `json:"message"`
+	Children []ErrorNode `json:"children,omitempty"`
+}
+
+type statusCoder interface {
+	statusCode() string
+}
+
+func (b *Base) statusCode() string {
+	return b.StatusCode
+}
+
+// DetailTree returns err as a tree of ErrorNode. Children are the sub
+// errors of ValidationError and ConflictError, the causes of
+// InternalError and PreconditionFailedError and the errors wrapped
+// by err.
+func DetailTree(err error) ErrorNode {
+	if err == nil {
+		return ErrorNode{}
+	}
+
+	node := ErrorNode{
+		Code:    codeOf(err),
+		Message: err.Error(),
+	}
+
+	var children []error
+	switch e := err.(type) {
+	case *ValidationError:
+		children = e.Errors
+	case *ConflictError:
+		children = e.Errors
+	case *InternalError:
+		children = []error{e.Err}
+	case *PreconditionFailedError:
+		children = []error{e.Err}
+	case interface{ Unwrap() []error }:
+		children = e.Unwrap()
+	case interface{ Unwrap() error }:
+		children = []error{e.Unwrap()}
+	}
+
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		node.Children = append(node.Children, DetailTree(child))
+	}
+
+	return node
+}
+
+// codeOf returns machine readable code of typed error err or empty
+// string.
+func codeOf(err error) string {
+	if sc, ok := err.(statusCoder); ok && sc.statusCode() != "" {
+		return sc.statusCode()
+	}
+	if c, ok := err.(coder); ok {
+		return string(c.Code())
+	}
+	if s, ok := err.(httpStatus); ok {
+		return string(CodeFromHttpStatus(s.HttpStatus()))
+	}
+	return ""
+}
+
+// statusTextCode returns snake case http status text, for example
+// not_found for http.StatusNotFound.
+func statusTextCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDetailTree(t *testing.T) {
+	err := Validation("article validation error").
+		AddError(New("title is required field")).
+		AddError(Validation("status is invalid").
+			AddError(New("must be one of the values [draft, published]")))
+
+	got := DetailTree(fmt.Errorf("create article: %w", err))
+
+	want := ErrorNode{
+		Message: "create article: article validation error",
+		Children: []ErrorNode{
+			{
+				Code:    "invalid_argument",
+				Message: "article validation error",
+				Children: []ErrorNode{
+					{Message: "title is required field"},
+					{
+						Code:    "invalid_argument",
+						Message: "status is invalid",
+						Children: []ErrorNode{
+							{Message: "must be one of the values [draft, published]"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetailTree() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDetailTreeCause(t *testing.T) {
+	err := Internal(New("connection refused"), "failed to fetch article")
+	err.StatusCode = "db_unavailable"
+
+	got := DetailTree(err)
+
+	want := ErrorNode{
+		Code:    "db_unavailable",
+		Message: "failed to fetch article",
+		Children: []ErrorNode{
+			{Message: "connection refused"},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetailTree() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDetailTreeAborted(t *testing.T) {
+	got := DetailTree(fmt.Errorf("update article: %w", Aborted("transaction aborted")))
+
+	want := ErrorNode{
+		Message: "update article: transaction aborted",
+		Children: []ErrorNode{
+			{Code: "aborted", Message: "transaction aborted"},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetailTree() = %+v, want %+v", got, want)
+	}
+}
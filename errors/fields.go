@@ -0,0 +1,61 @@
+package errors
+
+// fieldsError attaches key/value context to the wrapped error.
+type fieldsError struct {
+	err   error
+	key   string
+	value any
+}
+
+// WithField returns err annotated with key and value. The context can
+// be read with Fields and is written to http responses, err remains
+// reachable with Is and As. WithField returns nil if err is nil.
+func WithField(err error, key string, value any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldsError{err: err, key: key, value: value}
+}
+
+func (e *fieldsError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the annotated error.
+func (e *fieldsError) Unwrap() error {
+	return e.err
+}
+
+// Fields returns context attached with WithField to errors in err's
+// tree. When key is set more than once the outermost value is returned.
+func Fields(err error) map[string]any {
+	var fields map[string]any
+	collectFields(err, func(key string, value any) {
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	})
+	return fields
+}
+
+func collectFields(err error, fn func(key string, value any)) {
+	for err != nil {
+		if f, ok := err.(*fieldsError); ok {
+			fn(f.key, f.value)
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, child := range e.Unwrap() {
+				collectFields(child, fn)
+			}
+			return
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return
+		}
+	}
+}
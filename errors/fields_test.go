@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithField(t *testing.T) {
+	if err := WithField(nil, "article_id", 1); err != nil {
+		t.Errorf("WithField(nil) = %v, want nil", err)
+	}
+
+	err := WithField(NotFound("article not found"), "article_id", 1)
+	err = fmt.Errorf("get article: %w", WithField(err, "tenant", "acme"))
+	err = WithField(err, "article_id", 2)
+
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound() = false, want true")
+	}
+	if _, ok := AsNotFound(err); !ok {
+		t.Errorf("AsNotFound() = false, want true")
+	}
+	if err.Error() != "get article: article not found" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	want := map[string]any{"article_id": 2, "tenant": "acme"}
+	if got := Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+	if got := Fields(New("plain")); got != nil {
+		t.Errorf("Fields() = %v, want nil", got)
+	}
+}
+
+func TestJSONResponseFields(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := WithField(NotFound("article not found"), "article_id", "42")
+	if err := JSONResponse(w, err); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	var got HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"article_id": "42"}; !reflect.DeepEqual(got.Fields, want) {
+		t.Errorf("Fields = %v, want %v", got.Fields, want)
+	}
+}
+
+func TestResponseFieldsXML(t *testing.T) {
+	w := httptest.NewRecorder()
+	Response(xml.NewEncoder(w), w, WithField(NotFound("article not found"), "article_id", "42"))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	var got HttpResponse
+	if err := xml.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v, body %q", err, w.Body.String())
+	}
+	if got.Msg != "article not found" {
+		t.Errorf("Msg = %q, want %q", got.Msg, "article not found")
+	}
+}
@@ -3,12 +3,32 @@ package errors
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type httpResponse interface {
 	HttpResponse() HttpResponse
 }
 
+type httpStatus interface {
+	HttpStatus() int
+}
+
+// HttpStatus returns http status code of the first error in err's tree
+// which provides one. If there is no such error
+// http.StatusInternalServerError is returned.
+func HttpStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var status httpStatus
+	if As(FromContext(err), &status) {
+		return status.HttpStatus()
+	}
+	return http.StatusInternalServerError
+}
+
 type JSONResponseFunc func(*Base)
 
 type JSONResponseOption interface {
@@ -33,34 +53,72 @@ func WithTraceID(traceID string) JSONResponseFunc {
 
 type HttpResponse struct {
 	Base
-	Status int      `json:"-"`
-	Errors []string `json:"errors"`
+	Status int    `json:"-"`
+	Field  string `json:"field,omitempty"`
+	// Item is the conflicting resource of ConflictError.
+	Item any `json:"item,omitempty"`
+	// Cause is exposed only for InternalError, see SetCauseExposure.
+	Cause string `json:"cause,omitempty"`
+	// Stack is exposed only for InternalError, see SetDebug.
+	Stack []Frame `json:"stack,omitempty"`
+	// RetryAfter is the number of seconds to wait before retrying,
+	// used by TooManyRequestsError.
+	RetryAfter int `json:"retry_after,omitempty"`
+	// Fields is the context attached with WithField. It is not
+	// written as XML, encoding/xml does not support maps.
+	Fields map[string]any `json:"fields,omitempty" xml:"-"`
+	Errors []FieldError   `json:"errors"`
 }
 
-func JSONResponse(w http.ResponseWriter, err error, options ...JSONResponseOption) error {
-	w.Header().Set("Content-Type", "application/problem+json")
-	if err == nil {
-		return nil
+// AsError returns typed error matching the response status, so errors
+// received from other services can be handled same as local ones.
+// Responses with unknown status are returned as InternalError, custom
+// error types can be added with Register.
+func (r HttpResponse) AsError() error {
+	if fn, ok := registered(r.Status); ok {
+		return fn(r)
 	}
-again:
-	v, ok := err.(httpResponse)
-	if ok {
-		response := v.HttpResponse()
-		w.WriteHeader(response.Status)
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			return err
-		}
-		return nil
+
+	errs := make(MarshalableErrors, len(r.Errors))
+	for i := range r.Errors {
+		errs[i] = &r.Errors[i]
 	}
-	err = Unwrap(err)
-	if err != nil {
-		goto again
+
+	switch r.Status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		kind := KindMalformed
+		if r.Status == http.StatusUnprocessableEntity {
+			kind = KindUnprocessable
+		}
+		return &ValidationError{Base: r.Base, Kind: kind, Errors: errs}
+	case http.StatusConflict:
+		if r.StatusCode == string(CodeAborted) {
+			return &AbortedError{Base: r.Base}
+		}
+		return &ConflictError{Base: r.Base, Item: r.Item, Field: r.Field, Errors: errs}
+	case http.StatusNotFound:
+		return &NotFoundError{Base: r.Base}
+	case http.StatusPreconditionFailed:
+		return &PreconditionFailedError{Base: r.Base}
+	case http.StatusNotImplemented:
+		return &NotImplementedError{Base: r.Base}
+	case http.StatusUnauthorized:
+		return &UnauthenticatedError{Base: r.Base}
+	case http.StatusForbidden:
+		return &UnauthorizedError{Base: r.Base}
+	case http.StatusGatewayTimeout:
+		return &TimeoutError{Base: r.Base}
+	case StatusClientClosedRequest:
+		return &CanceledError{Base: r.Base}
+	case http.StatusTooManyRequests:
+		return &TooManyRequestsError{Base: r.Base, RetryAfter: time.Duration(r.RetryAfter) * time.Second}
 	}
+	return &InternalError{Base: r.Base}
+}
 
-	return json.NewEncoder(w).Encode(HttpResponse{
-		Base:   NewBase(err.Error()),
-		Status: http.StatusInternalServerError,
-	})
+func JSONResponse(w http.ResponseWriter, err error, options ...JSONResponseOption) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	return writeResponse(json.NewEncoder(w), w, err, "", options...)
 }
 
 type Encoder interface {
@@ -68,24 +126,41 @@ type Encoder interface {
 }
 
 func Response(encoder Encoder, w http.ResponseWriter, err error) {
+	writeResponse(encoder, w, err, "")
+}
+
+// writeResponse writes err with encoder. Message is translated to
+// locale when it is not empty, options are applied after.
+func writeResponse(encoder Encoder, w http.ResponseWriter, err error, locale string, options ...JSONResponseOption) error {
 	if err == nil {
-		return
+		return nil
 	}
+	err = FromContext(err)
+	orig := err
 again:
 	v, ok := err.(httpResponse)
 	if ok {
 		response := v.HttpResponse()
+		response.Fields = Fields(orig)
+		if locale != "" {
+			response.Localize(locale)
+		}
+		for _, opt := range options {
+			opt.Apply(&response.Base)
+		}
+		if response.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(response.RetryAfter))
+		}
 		w.WriteHeader(response.Status)
-		encoder.Encode(response)
-		return
+		return encoder.Encode(response)
 	}
 	err = Unwrap(err)
 	if err != nil {
 		goto again
 	}
 
-	encoder.Encode(HttpResponse{
-		Base:   NewBase(err.Error()),
-		Status: http.StatusInternalServerError,
-	})
+	// errors without http response are written as internal errors,
+	// so the cause is exposed only as allowed by SetCauseExposure
+	err = Internal(orig, "internal server error")
+	goto again
 }
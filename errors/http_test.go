@@ -1,8 +1,11 @@
 package errors
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -87,3 +90,26 @@ func TestJSONResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONResponseUntyped(t *testing.T) {
+	err := errors.New("pq: connection to 10.0.0.5 failed for user admin")
+
+	w := httptest.NewRecorder()
+	if err := JSONResponse(w, err, WithTraceID("trace-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if strings.Contains(w.Body.String(), "10.0.0.5") {
+		t.Errorf("body = %s, want cause hidden", w.Body.String())
+	}
+	var response HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Msg != "internal server error" || response.TraceID != "trace-1" {
+		t.Errorf("response = %+v, want generic message with trace id", response)
+	}
+}
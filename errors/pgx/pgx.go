@@ -0,0 +1,57 @@
+// Package pgx converts PostgreSQL errors to typed errors. It does not
+// depend on pgx, errors are matched by SQLState() method which is
+// implemented by *pgconn.PgError.
+package pgx
+
+import (
+	"strings"
+
+	"github.com/enverbisevac/libs/errors"
+)
+
+// SQLSTATE codes mapped by FromPgError.
+const (
+	UniqueViolation      = "23505"
+	ForeignKeyViolation  = "23503"
+	NotNullViolation     = "23502"
+	CheckViolation       = "23514"
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+// sqlStater is implemented by *pgconn.PgError.
+type sqlStater interface {
+	SQLState() string
+}
+
+// FromPgError converts PostgreSQL error in err's tree to typed error
+// by its SQLSTATE code:
+//   - 23505 unique violation to ConflictError
+//   - 23503 foreign key, 23502 not null, 23514 check violation and
+//     22xxx data exceptions to ValidationError
+//   - 40001 serialization failure and 40P01 deadlock to AbortedError,
+//     the transaction can be retried
+//
+// Other errors are returned unchanged. Database messages are not copied
+// to typed errors, so they are safe to return to clients.
+func FromPgError(err error) error {
+	var pgErr sqlStater
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	code := pgErr.SQLState()
+	switch {
+	case code == UniqueViolation:
+		return errors.Conflict("resource already exist")
+	case code == ForeignKeyViolation:
+		return errors.Validation("referenced resource does not exist or is still referenced")
+	case code == NotNullViolation, code == CheckViolation:
+		return errors.Validation("invalid field value")
+	case strings.HasPrefix(code, "22"):
+		return errors.Validation("invalid data")
+	case code == SerializationFailure, code == DeadlockDetected:
+		return errors.Aborted("concurrent update, please retry")
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package pgx
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/enverbisevac/libs/errors"
+)
+
+// pgError mimics *pgconn.PgError.
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string {
+	return "ERROR: (SQLSTATE " + e.code + ")"
+}
+
+func (e *pgError) SQLState() string {
+	return e.code
+}
+
+func TestFromPgError(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{code: UniqueViolation, want: http.StatusConflict},
+		{code: ForeignKeyViolation, want: http.StatusBadRequest},
+		{code: CheckViolation, want: http.StatusBadRequest},
+		{code: "22P02", want: http.StatusBadRequest},
+		{code: SerializationFailure, want: http.StatusConflict},
+		{code: "42P01", want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			err := FromPgError(fmt.Errorf("insert article: %w", &pgError{code: tt.code}))
+			if got := errors.HttpStatus(err); got != tt.want {
+				t.Errorf("HttpStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := FromPgError(&pgError{code: DeadlockDetected}); !errors.IsAborted(err) {
+		t.Errorf("FromPgError() = %T, want aborted error", err)
+	}
+
+	cause := errors.New("connection refused")
+	if err := FromPgError(cause); err != cause {
+		t.Errorf("FromPgError() = %v, want %v", err, cause)
+	}
+	if err := FromPgError(nil); err != nil {
+		t.Errorf("FromPgError(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+)
+
+// Recoverer is a middleware which recovers from panics in next and
+// writes them with JSONResponse as InternalError. Trace id is taken
+// from the request context, see ContextWithTraceID, and the panic is
+// logged at error level to the logr.Logger in the request context.
+// http.ErrAbortHandler is not recovered, so the server can abort the
+// response.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			cause, ok := rec.(error)
+			if !ok {
+				cause = fmt.Errorf("%v", rec)
+			}
+			logr.FromContextOrDiscard(r.Context()).Error(cause, "panic recovered",
+				"method", r.Method, "path", r.URL.Path, "trace_id", TraceIDFromContext(r.Context()))
+
+			JSONResponseCtx(r.Context(), w, Internal(cause, "internal server error"))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
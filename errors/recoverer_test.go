@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestRecoverer(t *testing.T) {
+	var logged []string
+	log := funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+
+	handler := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	ctx := logr.NewContext(ContextWithTraceID(r.Context(), "trace-1"), log)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r.WithContext(ctx))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	var response HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.TraceID != "trace-1" {
+		t.Errorf("TraceID = %q, want %q", response.TraceID, "trace-1")
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], `"error"="nil map"`) {
+		t.Errorf("logged = %v, want panic logged", logged)
+	}
+
+	t.Run("abort handler", func(t *testing.T) {
+		handler := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+		defer func() {
+			if rec := recover(); rec != http.ErrAbortHandler {
+				t.Errorf("recover() = %v, want %v", rec, http.ErrAbortHandler)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
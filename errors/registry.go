@@ -0,0 +1,38 @@
+package errors
+
+import "sync"
+
+var registry = struct {
+	sync.RWMutex
+	decoders map[int]func(HttpResponse) error
+}{
+	decoders: make(map[int]func(HttpResponse) error),
+}
+
+// Register makes HttpResponse.AsError return the error created by fn
+// for responses with status, so custom error types round-trip between
+// services same as the types of this package. Custom types are written
+// by JSONResponse and Response when they implement
+//
+//	HttpResponse() HttpResponse
+//
+// and get their code in DetailTree from Code() Code or HttpStatus() int
+// methods. Registering a status again replaces the previous function,
+// statuses of this package can be overridden too.
+//
+//	errors.Register(http.StatusPaymentRequired, func(r errors.HttpResponse) error {
+//		return &PaymentRequiredError{Base: r.Base}
+//	})
+func Register(status int, fn func(r HttpResponse) error) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.decoders[status] = fn
+}
+
+// registered returns function registered for status.
+func registered(status int) (func(HttpResponse) error, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	fn, ok := registry.decoders[status]
+	return fn, ok
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type paymentRequiredError struct {
+	Base
+}
+
+func (e *paymentRequiredError) Error() string {
+	return e.Msg
+}
+
+func (e *paymentRequiredError) Code() Code {
+	return "payment_required"
+}
+
+func (e *paymentRequiredError) HttpStatus() int {
+	return http.StatusPaymentRequired
+}
+
+func (e *paymentRequiredError) HttpResponse() HttpResponse {
+	return HttpResponse{
+		Base:   e.Base,
+		Status: e.HttpStatus(),
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(http.StatusPaymentRequired, func(r HttpResponse) error {
+		return &paymentRequiredError{Base: r.Base}
+	})
+	defer func() {
+		registry.Lock()
+		delete(registry.decoders, http.StatusPaymentRequired)
+		registry.Unlock()
+	}()
+
+	err := error(&paymentRequiredError{Base: NewBase("plan limit reached")})
+
+	w := httptest.NewRecorder()
+	Response(json.NewEncoder(w), w, err)
+	if w.Code != http.StatusPaymentRequired {
+		t.Errorf("Response() status = %d, want %d", w.Code, http.StatusPaymentRequired)
+	}
+
+	var response HttpResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	response.Status = w.Code
+
+	got, ok := response.AsError().(*paymentRequiredError)
+	if !ok {
+		t.Fatalf("AsError() = %T, want *paymentRequiredError", response.AsError())
+	}
+	if got.Error() != "plan limit reached" {
+		t.Errorf("AsError() message = %q, want %q", got.Error(), "plan limit reached")
+	}
+
+	if code := DetailTree(err).Code; code != "payment_required" {
+		t.Errorf("DetailTree() code = %q, want payment_required", code)
+	}
+}
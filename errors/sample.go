@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	sampleMu sync.Mutex
+	samples  = make(map[string]time.Time)
+	// now is replaced in tests.
+	now = time.Now
+)
+
+// Sample returns err and whether it should be logged, which is true
+// only for the first occurrence of key in the window, for example:
+//
+//	if err, ok := errors.Sample("db.fetch", err, time.Minute); ok {
+//		logger.Error(err, "fetch failed")
+//	}
+//
+// Nil err is never logged.
+func Sample(key string, err error, window time.Duration) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	sampleMu.Lock()
+	defer sampleMu.Unlock()
+
+	t := now()
+	if until, ok := samples[key]; ok && t.Before(until) {
+		return err, false
+	}
+	// drop expired keys so the map does not grow with stale keys
+	for k, until := range samples {
+		if !t.Before(until) {
+			delete(samples, k)
+		}
+	}
+	samples[key] = t.Add(window)
+	return err, true
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSample(t *testing.T) {
+	current := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	cause := New("connection refused")
+	sample := func(key string) bool {
+		err, ok := Sample(key, cause, time.Minute)
+		if err != cause {
+			t.Fatalf("Sample() returned %v, want %v", err, cause)
+		}
+		return ok
+	}
+
+	if !sample("db") {
+		t.Error("first occurrence should be logged")
+	}
+	current = current.Add(30 * time.Second)
+	if sample("db") {
+		t.Error("occurrence within window should be suppressed")
+	}
+	if !sample("cache") {
+		t.Error("first occurrence of other key should be logged")
+	}
+	current = current.Add(31 * time.Second)
+	if !sample("db") {
+		t.Error("occurrence after window should be logged")
+	}
+	if _, ok := Sample("db", nil, time.Minute); ok {
+		t.Error("nil error should not be logged")
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// maxFrames is the maximum number of frames captured by InternalError.
+const maxFrames = 32
+
+// maxDebugFrames is the maximum number of frames written to http
+// responses in debug mode.
+const maxDebugFrames = 10
+
+// Frame is a single stack frame.
+type Frame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+var debugMode atomic.Bool
+
+// SetDebug enables writing of InternalError stack in http responses.
+// It must not be enabled in production, stacks expose internal details
+// of the service.
+func SetDebug(enabled bool) {
+	debugMode.Store(enabled)
+}
+
+// callers returns stack frames of the caller, skip is the number of
+// frames to skip where 0 identifies the caller of callers.
+func callers(skip int) []Frame {
+	pcs := make([]uintptr, maxFrames)
+	n := runtime.Callers(skip+2, pcs)
+	if n == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]Frame, 0, n)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{
+			Func: frame.Function,
+			File: frame.File,
+			Line: frame.Line,
+		})
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// debugStack returns stack trimmed of runtime frames when debug mode
+// is enabled, otherwise nil.
+func debugStack(stack []Frame) []Frame {
+	if !debugMode.Load() {
+		return nil
+	}
+	trimmed := make([]Frame, 0, len(stack))
+	for _, frame := range stack {
+		if strings.HasPrefix(frame.Func, "runtime.") {
+			continue
+		}
+		trimmed = append(trimmed, frame)
+		if len(trimmed) == maxDebugFrames {
+			break
+		}
+	}
+	return trimmed
+}
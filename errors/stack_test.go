@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInternalError_Frames(t *testing.T) {
+	err := Internal(New("connection refused"), "query failed")
+
+	frames := err.Frames()
+	if len(frames) == 0 {
+		t.Fatal("expected frames, got none")
+	}
+	if !strings.HasSuffix(frames[0].Func, "TestInternalError_Frames") {
+		t.Errorf("Func = %q, want caller of Internal", frames[0].Func)
+	}
+	if !strings.HasSuffix(frames[0].File, "stack_test.go") || frames[0].Line == 0 {
+		t.Errorf("frame = %+v, want location in stack_test.go", frames[0])
+	}
+}
+
+func TestSetDebug(t *testing.T) {
+	tests := []struct {
+		name  string
+		debug bool
+	}{
+		{name: "production", debug: false},
+		{name: "debug", debug: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDebug(tt.debug)
+			defer SetDebug(false)
+
+			w := httptest.NewRecorder()
+			if err := JSONResponse(w, Internal(New("connection refused"), "query failed")); err != nil {
+				t.Fatal(err)
+			}
+
+			var got HttpResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.debug {
+				if len(got.Stack) != 0 {
+					t.Errorf("stack = %v, want none", got.Stack)
+				}
+				return
+			}
+			if len(got.Stack) == 0 || len(got.Stack) > maxDebugFrames {
+				t.Fatalf("stack length = %d, want 1..%d", len(got.Stack), maxDebugFrames)
+			}
+			for _, frame := range got.Stack {
+				if strings.HasPrefix(frame.Func, "runtime.") {
+					t.Errorf("stack contains runtime frame %q", frame.Func)
+				}
+			}
+		})
+	}
+}
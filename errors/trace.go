@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+type traceIDKey struct{}
+
+var (
+	traceKeyMu sync.RWMutex
+	traceKey   any = traceIDKey{}
+)
+
+// SetTraceIDKey sets the context key under which trace id is stored,
+// so ids set by other middlewares can be used in responses. Values
+// stored under the key must be strings or fmt.Stringer. Nil restores
+// the default key used by ContextWithTraceID.
+func SetTraceIDKey(key any) {
+	traceKeyMu.Lock()
+	defer traceKeyMu.Unlock()
+	if key == nil {
+		key = traceIDKey{}
+	}
+	traceKey = key
+}
+
+func getTraceIDKey() any {
+	traceKeyMu.RLock()
+	defer traceKeyMu.RUnlock()
+	return traceKey
+}
+
+// ContextWithTraceID returns a copy of ctx which carries trace id.
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, getTraceIDKey(), id)
+}
+
+// TraceIDFromContext returns trace id stored in ctx or empty string.
+func TraceIDFromContext(ctx context.Context) string {
+	switch id := ctx.Value(getTraceIDKey()).(type) {
+	case string:
+		return id
+	case fmt.Stringer:
+		return id.String()
+	}
+	return ""
+}
+
+// JSONResponseCtx is JSONResponse which sets trace id from ctx on the
+// response and translates the message to the locale stored in ctx, see
+// ContextWithLocale. Options are applied after and can override the
+// trace id.
+func JSONResponseCtx(ctx context.Context, w http.ResponseWriter, err error, options ...JSONResponseOption) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	return writeResponse(json.NewEncoder(w), w, err, LocaleFromContext(ctx), ctxOptions(ctx, options)...)
+}
+
+// ResponseCtx is Response which sets trace id and translates the
+// message from ctx same as JSONResponseCtx.
+func ResponseCtx(ctx context.Context, encoder Encoder, w http.ResponseWriter, err error) {
+	writeResponse(encoder, w, err, LocaleFromContext(ctx), ctxOptions(ctx, nil)...)
+}
+
+// ctxOptions returns options with trace id from ctx prepended.
+func ctxOptions(ctx context.Context, options []JSONResponseOption) []JSONResponseOption {
+	if id := TraceIDFromContext(ctx); id != "" {
+		return append([]JSONResponseOption{WithTraceID(id)}, options...)
+	}
+	return options
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func TestJSONResponseCtx(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "typed error", err: NotFound("article not found")},
+		{name: "std error", err: New("connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ContextWithTraceID(context.Background(), "trace-1")
+
+			w := httptest.NewRecorder()
+			if err := JSONResponseCtx(ctx, w, tt.err); err != nil {
+				t.Fatal(err)
+			}
+			var got HttpResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.TraceID != "trace-1" {
+				t.Errorf("JSONResponseCtx() TraceID = %q, want %q", got.TraceID, "trace-1")
+			}
+
+			w = httptest.NewRecorder()
+			ResponseCtx(ctx, json.NewEncoder(w), w, tt.err)
+			got = HttpResponse{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.TraceID != "trace-1" {
+				t.Errorf("ResponseCtx() TraceID = %q, want %q", got.TraceID, "trace-1")
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	ctx := ContextWithTraceID(context.Background(), "trace-1")
+	if err := JSONResponseCtx(ctx, w, NotFound("article not found"), WithTraceID("trace-2")); err != nil {
+		t.Fatal(err)
+	}
+	var got HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.TraceID != "trace-2" {
+		t.Errorf("TraceID = %q, want option to override context", got.TraceID)
+	}
+}
+
+func TestSetTraceIDKey(t *testing.T) {
+	SetTraceIDKey(requestIDKey{})
+	defer SetTraceIDKey(nil)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	if got := TraceIDFromContext(ctx); got != "req-1" {
+		t.Errorf("TraceIDFromContext() = %q, want %q", got, "req-1")
+	}
+
+	SetTraceIDKey(nil)
+	if got := TraceIDFromContext(ctx); got != "" {
+		t.Errorf("TraceIDFromContext() = %q, want empty", got)
+	}
+}
+
+func TestResponseCtxUntyped(t *testing.T) {
+	ctx := ContextWithTraceID(context.Background(), "trace-1")
+
+	w := httptest.NewRecorder()
+	ResponseCtx(ctx, json.NewEncoder(w), w, New("pq: connection to 10.0.0.5 failed for user admin"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if strings.Contains(w.Body.String(), "10.0.0.5") {
+		t.Errorf("body = %s, want cause hidden", w.Body.String())
+	}
+	var got HttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Msg != "internal server error" || got.TraceID != "trace-1" {
+		t.Errorf("response = %+v, want generic message with trace id", got)
+	}
+}
@@ -0,0 +1,176 @@
+package httputil
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a single media range from the Accept header.
+type mediaRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// specificity returns 2 for exact media type, 1 for type/* and 0 for
+// */* range.
+func (m mediaRange) specificity() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// matches reports whether media type typ/subtype is in the range.
+func (m mediaRange) matches(typ, subtype string) bool {
+	return (m.typ == "*" || m.typ == typ) && (m.subtype == "*" || m.subtype == subtype)
+}
+
+// parseAccept parses Accept header value into media ranges. Parameters
+// other than q are ignored and invalid ranges are skipped.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			if mediaType != "*" {
+				continue
+			}
+			subtype = "*"
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q})
+	}
+	return ranges
+}
+
+// negotiate returns the supported media type with the highest quality
+// in the Accept header, ties are resolved by the order of supported.
+// Quality of a media type is taken from the most specific range which
+// matches it. Empty string is returned when no type is acceptable.
+func negotiate(accept string, supported ...string) string {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		if len(supported) == 0 {
+			return ""
+		}
+		return supported[0]
+	}
+
+	best, bestQ := "", 0.0
+	for _, s := range supported {
+		typ, subtype, _ := strings.Cut(s, "/")
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			if r.matches(typ, subtype) && r.specificity() > specificity {
+				q, specificity = r.q, r.specificity()
+			}
+		}
+		if q > bestQ {
+			best, bestQ = s, q
+		}
+	}
+	return best
+}
+
+// languageRange is a single language range from the Accept-Language
+// header.
+type languageRange struct {
+	tag string
+	q   float64
+}
+
+// parseAcceptLanguage parses Accept-Language header value into
+// lowercased language ranges, malformed ranges are skipped.
+func parseAcceptLanguage(header string) []languageRange {
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || strings.ContainsAny(tag, " \t") {
+			continue
+		}
+		q := 1.0
+		if params = strings.TrimSpace(params); params != "" {
+			v, ok := strings.CutPrefix(params, "q=")
+			if !ok {
+				continue
+			}
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, languageRange{tag: tag, q: q})
+	}
+	return ranges
+}
+
+// languageMatch returns how specifically range r matches language tag,
+// -1 when it doesn't match. Exact match is the most specific, followed
+// by range which is a prefix of the tag (en for en-US), tag which is a
+// prefix of the range (en-US for en) and the * wildcard.
+func languageMatch(r, tag string) int {
+	switch {
+	case r == tag:
+		return 3
+	case strings.HasPrefix(tag, r+"-"):
+		return 2
+	case strings.HasPrefix(r, tag+"-"):
+		return 1
+	case r == "*":
+		return 0
+	}
+	return -1
+}
+
+// PreferredLanguage returns the supported language with the highest
+// quality in the Accept-Language header of r, ties are resolved by the
+// order of supported:
+//
+//	// Accept-Language: de-CH, de;q=0.9, en;q=0.8
+//	lang := httputil.PreferredLanguage(r, "en", "de") // de
+//
+// Quality of a language is taken from the most specific range which
+// matches it. The first supported language is returned when the header
+// is missing or malformed or no supported language is acceptable.
+func PreferredLanguage(r *http.Request, supported ...string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	ranges := parseAcceptLanguage(r.Header.Get("Accept-Language"))
+	best, bestQ := supported[0], 0.0
+	for _, s := range supported {
+		tag := strings.ToLower(s)
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			if m := languageMatch(r.tag, tag); m > specificity {
+				q, specificity = r.q, m
+			}
+		}
+		if q > bestQ {
+			best, bestQ = s, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,65 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	supported := []string{"application/json", "application/xml", "application/yaml"}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: "application/json"},
+		{accept: "application/xml", want: "application/xml"},
+		{accept: "application/json; charset=utf-8", want: "application/json"},
+		{accept: "application/xml, application/json;q=0.9", want: "application/xml"},
+		{accept: "application/json;q=0.5, application/yaml", want: "application/yaml"},
+		{accept: "*/*", want: "application/json"},
+		{accept: "application/*", want: "application/json"},
+		{accept: "application/*;q=0.5, application/yaml", want: "application/yaml"},
+		{accept: "*/*;q=0.1, application/xml;q=0.2", want: "application/xml"},
+		{accept: "application/*, application/json;q=0", want: "application/xml"},
+		{accept: "text/html", want: ""},
+		{accept: "text/html, invalid;;, application/xml;q=x, application/yaml;q=0.3", want: "application/yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiate(tt.accept, supported...))
+		})
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	supported := []string{"en", "de", "fr-CA"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: "en"},
+		{header: "de", want: "de"},
+		{header: "de-CH, de;q=0.9, en;q=0.8", want: "de"},
+		{header: "en;q=0.5, de;q=0.7", want: "de"},
+		{header: "FR-ca;q=0.9, en;q=0.8", want: "fr-CA"},
+		{header: "fr;q=0.9, en;q=0.8", want: "fr-CA"},
+		{header: "*", want: "en"},
+		{header: "*;q=0.5, de;q=0.6", want: "de"},
+		{header: "*, en;q=0", want: "de"},
+		{header: "it, es", want: "en"},
+		{header: "de;q=x, fr-CA;level=1, en;q=2, bad tag, de-AT;q=0.4", want: "de"},
+		{header: ";;;", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept-Language", tt.header)
+			assert.Equal(t, tt.want, PreferredLanguage(r, supported...))
+		})
+	}
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	assert.Empty(t, PreferredLanguage(r))
+}
@@ -0,0 +1,100 @@
+package httputil
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	liberrors "github.com/enverbisevac/libs/errors"
+	"github.com/enverbisevac/libs/validator"
+)
+
+// validatable is implemented by request types which validate
+// themselves after decoding.
+type validatable interface {
+	Validate() error
+}
+
+// BindMiddleware returns handler which decodes path, query, header and
+// body values into T using d, sanitizes fields with `sanitize` tag,
+// checks rules of the `validate` tag, see validator.ValidateStruct, and
+// validates the value when T implements Validate() error. On success
+// next is called with decoded value, otherwise error is written in
+// format negotiated from the request Accept header, for example:
+//
+//	type CreateArticle struct {
+//		ID   int64       `path:"id"`
+//		Body ArticleBody `body:"json"`
+//	}
+//
+//	type ArticleBody struct {
+//		Title string `json:"title" sanitize:"trim" validate:"required,max=120"`
+//	}
+//
+//	r.Post("/articles", httputil.BindMiddleware(decoder,
+//		func(w http.ResponseWriter, r *http.Request, req CreateArticle) {
+//			...
+//		}))
+//
+// When d is nil a Decoder without path param function is used.
+func BindMiddleware[T any](d *Decoder, next func(w http.ResponseWriter, r *http.Request, data T)) http.HandlerFunc {
+	if d == nil {
+		d = NewDecoder(nil)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		var data T
+		if err := bind(d, r, &data); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		next(w, r, data)
+	}
+}
+
+// writeError writes err in format negotiated from the request Accept
+// header. Errors without http status are written as internal errors.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := liberrors.AsCode(err); !ok {
+		err = liberrors.Internal(err, "internal server error")
+	}
+	enc, contentType := negotiateEncoder(w, r)
+	w.Header().Set("Content-Type", contentType)
+	liberrors.Response(enc, w, err)
+}
+
+func bind(d *Decoder, r *http.Request, data any) error {
+	if err := d.Decode(r, data); err != nil {
+		return invalidInput(err)
+	}
+	if reflect.TypeOf(data).Elem().Kind() == reflect.Struct {
+		if err := validator.SanitizeStruct(data); err != nil {
+			return err
+		}
+		if err := validator.ValidateStruct(data); err != nil {
+			return err
+		}
+	}
+	if v, ok := data.(validatable); ok {
+		if err := v.Validate(); err != nil {
+			return invalidInput(err)
+		}
+	}
+	return nil
+}
+
+// invalidInput converts plain errors to ValidationError, typed errors
+// are returned unchanged. Fields of DecodeError are added as
+// errors.FieldError entries.
+func invalidInput(err error) error {
+	if _, ok := liberrors.AsCode(err); ok {
+		return err
+	}
+	verr := liberrors.Validation("%s", err.Error())
+	var derr *DecodeError
+	if errors.As(err, &derr) {
+		for _, f := range derr.Fields {
+			verr.AddError(&liberrors.FieldError{Field: f.Name, Message: f.Err.Error()})
+		}
+	}
+	return verr
+}
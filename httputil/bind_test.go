@@ -0,0 +1,98 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	liberrors "github.com/enverbisevac/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type articleBody struct {
+	Title string `json:"title" sanitize:"trim" validate:"required,max=20"`
+}
+
+type createArticle struct {
+	ID   int64       `path:"id"`
+	Body articleBody `body:"article"`
+}
+
+func (a createArticle) Validate() error {
+	if a.Body.Title == "draft" {
+		return liberrors.Validation("title is reserved")
+	}
+	return nil
+}
+
+func TestBindMiddleware(t *testing.T) {
+	var got createArticle
+	handler := BindMiddleware(NewDecoder(pathParams(map[string]string{"id": "7"})),
+		func(w http.ResponseWriter, r *http.Request, data createArticle) {
+			got = data
+			w.WriteHeader(http.StatusCreated)
+		})
+
+	t.Run("valid", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/articles/7", strings.NewReader(`{"title":"  test "}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, createArticle{ID: 7, Body: articleBody{Title: "test"}}, got)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		got = createArticle{}
+		r := httptest.NewRequest("POST", "/articles/7", strings.NewReader(`{"title":"   "}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `{"field":"title","message":"is required","code":"required"}`)
+		assert.Equal(t, createArticle{}, got)
+	})
+
+	t.Run("validate method", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/articles/7", strings.NewReader(`{"title":"draft"}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "title is reserved")
+	})
+
+	t.Run("decode error fields", func(t *testing.T) {
+		handler := BindMiddleware(NewDecoder(pathParams(map[string]string{"id": "x"})),
+			func(w http.ResponseWriter, r *http.Request, data createArticle) {
+				w.WriteHeader(http.StatusCreated)
+			})
+		r := httptest.NewRequest("POST", "/articles/x", strings.NewReader(`{"title":"test"}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response liberrors.HttpResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.Len(t, response.Errors, 1) {
+			assert.Equal(t, "id", response.Errors[0].Field)
+			assert.NotEmpty(t, response.Errors[0].Message)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/articles/7", strings.NewReader(`{"title":`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
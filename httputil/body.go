@@ -0,0 +1,367 @@
+package httputil
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	liberrors "github.com/enverbisevac/libs/errors"
+)
+
+// Body formats supported by decodeBody.
+const (
+	FormatJSON = "json"
+	FormatXML  = "xml"
+)
+
+// ErrBodyTooLarge is returned when request body exceeds the limit set
+// by MaxBodyBytes.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// BodyDecoder decodes request body read from r into v.
+type BodyDecoder func(r io.Reader, v any) error
+
+// defaultBodyDecoders are used by every Decoder, keyed by format.
+var defaultBodyDecoders = map[string]BodyDecoder{
+	FormatJSON: func(r io.Reader, v any) error {
+		return json.NewDecoder(r).Decode(v)
+	},
+	FormatXML: func(r io.Reader, v any) error {
+		return decodeXML(xml.NewDecoder(r), v)
+	},
+}
+
+// defaultContentTypes maps media types to default formats.
+var defaultContentTypes = map[string]string{
+	"application/json": FormatJSON,
+	"application/xml":  FormatXML,
+	"text/xml":         FormatXML,
+}
+
+// bodyFormat returns format from the request Content-Type header,
+// json is used when content type is missing or unknown.
+func (d *Decoder) bodyFormat(r *http.Request) string {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if format, ok := d.contentTypes[mediaType]; ok {
+		return format
+	}
+	if format, ok := defaultContentTypes[mediaType]; ok {
+		return format
+	}
+	return FormatJSON
+}
+
+// bodyDecoder returns decoder registered for the format.
+func (d *Decoder) bodyDecoder(format string) (BodyDecoder, bool) {
+	if fn, ok := d.bodyDecoders[format]; ok {
+		return fn, true
+	}
+	fn, ok := defaultBodyDecoders[format]
+	return fn, ok
+}
+
+// decodeBody decodes request body into v. When format is empty it is
+// selected from the request Content-Type header. Empty body leaves v
+// unchanged unless the decoder requires body for the request method.
+func (d *Decoder) decodeBody(r *http.Request, format string, v any) error {
+	_, err := d.readBody(r, format, v)
+	return err
+}
+
+// readBody decodes request body into v same as decodeBody and reports
+// whether the body was present.
+func (d *Decoder) readBody(r *http.Request, format string, v any) (bool, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return false, d.emptyBody(r)
+	}
+	if format == "" {
+		format = d.bodyFormat(r)
+	}
+
+	decode, ok := d.bodyDecoder(format)
+	if !ok {
+		return false, fmt.Errorf("unsupported body format: %s", format)
+	}
+	if format == FormatJSON && d.lenientNumbers {
+		decode = decodeLenientJSON
+	}
+	err := decode(d.limitBody(r.Body), v)
+	if errors.Is(err, io.EOF) {
+		return false, d.emptyBody(r)
+	}
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return false, fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, maxErr.Limit)
+	}
+	if err != nil {
+		return false, fmt.Errorf("body decode error: %w", err)
+	}
+	return true, nil
+}
+
+// limitBody wraps body with http.MaxBytesReader when the decoder has
+// body limit.
+func (d *Decoder) limitBody(body io.ReadCloser) io.ReadCloser {
+	if d.maxBodyBytes <= 0 {
+		return body
+	}
+	return http.MaxBytesReader(nil, body, d.maxBodyBytes)
+}
+
+// StreamError is yielded by a streamed body iterator when the element
+// at Index could not be decoded. Index is -1 when the body is not a
+// JSON array.
+type StreamError struct {
+	Index int
+	Err   error
+}
+
+// Error interface method.
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("body element %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the cause.
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// streamBody sets iterator over elements of the JSON array body on the
+// field, which must be func(yield func(T, error) bool), for example:
+//
+//	type request struct {
+//		Items func(yield func(Item, error) bool) `body:"items,json,stream"`
+//	}
+//
+//	req.Items(func(item Item, err error) bool {
+//		if err != nil {
+//			// *StreamError with element index
+//			return false
+//		}
+//		...
+//		return true
+//	})
+//
+// Elements are decoded while iterating, so the iterator must be called
+// before handler returns and only once. Iteration stops after the first
+// error.
+func (d *Decoder) streamBody(r *http.Request, format string, field reflect.Value) error {
+	if format != "" && format != FormatJSON {
+		return fmt.Errorf("unsupported stream body format: %s", format)
+	}
+	typ := field.Type()
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.NumOut() != 0 {
+		return fmt.Errorf("stream body requires func(yield func(T, error) bool) field, got %v", typ)
+	}
+	yieldType := typ.In(0)
+	if yieldType.Kind() != reflect.Func || yieldType.NumIn() != 2 || yieldType.In(1) != errorType ||
+		yieldType.NumOut() != 1 || yieldType.Out(0).Kind() != reflect.Bool {
+		return fmt.Errorf("stream body requires func(yield func(T, error) bool) field, got %v", typ)
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		if err := d.emptyBody(r); err != nil {
+			return err
+		}
+	}
+
+	elemType := yieldType.In(0)
+	field.Set(reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		yield := func(v reflect.Value, err error) bool {
+			errValue := reflect.Zero(errorType)
+			if err != nil {
+				errValue = reflect.ValueOf(err)
+			}
+			return args[0].Call([]reflect.Value{v, errValue})[0].Bool()
+		}
+		if r.Body == nil {
+			return nil
+		}
+		streamJSON(d.limitBody(r.Body), elemType, yield)
+		return nil
+	}))
+	return nil
+}
+
+// streamJSON decodes elements of JSON array read from body and passes
+// them to yield until it returns false.
+func streamJSON(body io.Reader, elemType reflect.Type, yield func(reflect.Value, error) bool) {
+	zero := reflect.Zero(elemType)
+	dec := json.NewDecoder(body)
+	tok, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return
+	}
+	if err != nil {
+		yield(zero, &StreamError{Index: -1, Err: err})
+		return
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		yield(zero, &StreamError{Index: -1, Err: fmt.Errorf("expected JSON array, got %v", tok)})
+		return
+	}
+	i := 0
+	for ; dec.More(); i++ {
+		elem := reflect.New(elemType)
+		if err := dec.Decode(elem.Interface()); err != nil {
+			yield(zero, &StreamError{Index: i, Err: err})
+			return
+		}
+		if !yield(elem.Elem(), nil) {
+			return
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		yield(zero, &StreamError{Index: i, Err: err})
+	}
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decodeLenientJSON decodes JSON from r into v, numbers are accepted
+// for string fields and numeric strings for number fields.
+func decodeLenientJSON(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	data, err := json.Marshal(coerceNumbers(value, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// coerceNumbers converts json.Number values to strings where typ is
+// a string and numeric strings to json.Number where typ is a number.
+func coerceNumbers(value any, typ reflect.Type) any {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+		return value
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		if typ.Kind() == reflect.String {
+			return string(v)
+		}
+	case string:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return json.Number(v)
+			}
+		}
+	case []any:
+		if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+			for i := range v {
+				v[i] = coerceNumbers(v[i], typ.Elem())
+			}
+		}
+	case map[string]any:
+		switch typ.Kind() {
+		case reflect.Map:
+			for key := range v {
+				v[key] = coerceNumbers(v[key], typ.Elem())
+			}
+		case reflect.Struct:
+			coerceFields(v, typ)
+		}
+	}
+	return value
+}
+
+// coerceFields coerces values of object m by types of struct fields
+// matched by json name, case-insensitively as encoding/json does.
+func coerceFields(m map[string]any, typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				coerceFields(m, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		for key, value := range m {
+			if strings.EqualFold(key, name) {
+				m[key] = coerceNumbers(value, field.Type)
+			}
+		}
+	}
+}
+
+// emptyBody returns validation error when body is required for the
+// request method.
+func (d *Decoder) emptyBody(r *http.Request) error {
+	if !d.requireBody {
+		return nil
+	}
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return liberrors.Validation("request body is required")
+	}
+	return nil
+}
+
+// decodeXML decodes xml document into v. When v is a pointer to slice
+// children of the root element are decoded as slice elements, so
+// <ids><id>1</id><id>2</id></ids> can be decoded into []int.
+func decodeXML(dec *xml.Decoder, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Slice ||
+		rv.Elem().Type().Elem().Kind() == reflect.Uint8 {
+		return dec.Decode(v)
+	}
+
+	slice := rv.Elem()
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				// root element
+				depth++
+				continue
+			}
+			elem := reflect.New(slice.Type().Elem())
+			if err := dec.DecodeElement(elem.Interface(), &t); err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
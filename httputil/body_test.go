@@ -0,0 +1,266 @@
+package httputil
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enverbisevac/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type lineItem struct {
+	SKU string `json:"sku" xml:"sku"`
+	Qty int    `json:"qty" xml:"qty"`
+}
+
+func TestDecode_TopLevelArray(t *testing.T) {
+	t.Run("json ints into target", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("[1,2,3]"))
+		var ids []int
+		assert.NoError(t, Decode(r, pathParams(nil), &ids))
+		assert.Equal(t, []int{1, 2, 3}, ids)
+	})
+
+	t.Run("json structs into body field", func(t *testing.T) {
+		type request struct {
+			OrderID int        `path:"id"`
+			Items   []lineItem `body:"items"`
+		}
+		r, _ := http.NewRequest("POST", "/orders/7/items",
+			strings.NewReader(`[{"sku":"a","qty":1},{"sku":"b","qty":2}]`))
+		r.Header.Set("Content-Type", "application/json")
+
+		var req request
+		assert.NoError(t, Decode(r, pathParams(map[string]string{"id": "7"}), &req))
+		assert.Equal(t, 7, req.OrderID)
+		assert.Equal(t, []lineItem{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}}, req.Items)
+	})
+
+	t.Run("xml list of ints", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("<ids><id>1</id><id>2</id></ids>"))
+		r.Header.Set("Content-Type", "application/xml; charset=utf-8")
+		var ids []int
+		assert.NoError(t, Decode(r, pathParams(nil), &ids))
+		assert.Equal(t, []int{1, 2}, ids)
+	})
+
+	t.Run("xml list of structs", func(t *testing.T) {
+		type request struct {
+			Items []lineItem `body:"items,xml"`
+		}
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(
+			"<items><item><sku>a</sku><qty>1</qty></item><item><sku>b</sku><qty>2</qty></item></items>"))
+		var req request
+		assert.NoError(t, Decode(r, pathParams(nil), &req))
+		assert.Equal(t, []lineItem{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}}, req.Items)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(`[1,"a"]`))
+		var ids []int
+		assert.Error(t, Decode(r, pathParams(nil), &ids))
+	})
+}
+
+func TestDecoder_RequireBody(t *testing.T) {
+	type request struct {
+		Item lineItem `body:"item"`
+	}
+
+	decoder := NewDecoder(pathParams(nil), RequireBody())
+
+	t.Run("empty body", func(t *testing.T) {
+		for _, body := range []io.Reader{nil, strings.NewReader(""), strings.NewReader("  \n")} {
+			r, _ := http.NewRequest("POST", "/", body)
+			err := decoder.Decode(r, &request{})
+			assert.True(t, errors.IsValidation(err), "got: %v", err)
+			assert.Equal(t, http.StatusBadRequest, errors.HttpStatus(err))
+		}
+	})
+
+	t.Run("present body", func(t *testing.T) {
+		r, _ := http.NewRequest("PUT", "/", strings.NewReader(`{"sku":"a","qty":1}`))
+		var req request
+		assert.NoError(t, decoder.Decode(r, &req))
+		assert.Equal(t, lineItem{SKU: "a", Qty: 1}, req.Item)
+	})
+
+	t.Run("get without body", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/", nil)
+		assert.NoError(t, decoder.Decode(r, &request{}))
+	})
+
+	t.Run("optional by default", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(""))
+		assert.NoError(t, Decode(r, pathParams(nil), &request{}))
+	})
+}
+
+func TestDecoder_WithBodyDecoder(t *testing.T) {
+	type request struct {
+		Item lineItem `body:"item"`
+	}
+	type taggedRequest struct {
+		Item lineItem `body:"item,yaml"`
+	}
+
+	decoder := NewDecoder(pathParams(nil), WithBodyDecoder("yaml", func(r io.Reader, v any) error {
+		return yaml.NewDecoder(r).Decode(v)
+	}, "application/yaml"))
+
+	t.Run("by content type", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("sku: a\nqty: 2\n"))
+		r.Header.Set("Content-Type", "application/yaml; charset=utf-8")
+		r.Header.Set("Accept", "application/json")
+
+		var req request
+		assert.NoError(t, decoder.Decode(r, &req))
+		assert.Equal(t, lineItem{SKU: "a", Qty: 2}, req.Item)
+	})
+
+	t.Run("by tag", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("sku: b\nqty: 3\n"))
+
+		var req taggedRequest
+		assert.NoError(t, decoder.Decode(r, &req))
+		assert.Equal(t, lineItem{SKU: "b", Qty: 3}, req.Item)
+	})
+
+	t.Run("json stays default", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(`{"sku":"c","qty":4}`))
+
+		var req request
+		assert.NoError(t, decoder.Decode(r, &req))
+		assert.Equal(t, lineItem{SKU: "c", Qty: 4}, req.Item)
+	})
+
+	t.Run("unregistered format", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("sku: d\n"))
+
+		assert.ErrorContains(t, Decode(r, pathParams(nil), &taggedRequest{}), "unsupported body format")
+	})
+}
+
+func TestDecode_StreamBody(t *testing.T) {
+	type request struct {
+		OrderID int                                    `path:"id"`
+		Items   func(yield func(lineItem, error) bool) `body:"items,json,stream"`
+	}
+
+	collect := func(body string) ([]lineItem, error) {
+		r, _ := http.NewRequest("POST", "/orders/7/items", strings.NewReader(body))
+		var req request
+		if err := Decode(r, pathParams(map[string]string{"id": "7"}), &req); err != nil {
+			return nil, err
+		}
+		var items []lineItem
+		var iterErr error
+		req.Items(func(item lineItem, err error) bool {
+			if err != nil {
+				iterErr = err
+				return false
+			}
+			items = append(items, item)
+			return true
+		})
+		return items, iterErr
+	}
+
+	t.Run("elements", func(t *testing.T) {
+		items, err := collect(`[{"sku":"a","qty":1},{"sku":"b","qty":2}]`)
+		assert.NoError(t, err)
+		assert.Equal(t, []lineItem{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}}, items)
+	})
+
+	t.Run("element error", func(t *testing.T) {
+		items, err := collect(`[{"sku":"a","qty":1},{"sku":"b","qty":"x"}]`)
+		var serr *StreamError
+		assert.True(t, errors.As(err, &serr))
+		assert.Equal(t, 1, serr.Index)
+		assert.Equal(t, []lineItem{{SKU: "a", Qty: 1}}, items)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		_, err := collect(`{"sku":"a"}`)
+		var serr *StreamError
+		assert.True(t, errors.As(err, &serr))
+		assert.Equal(t, -1, serr.Index)
+	})
+
+	t.Run("invalid field type", func(t *testing.T) {
+		type invalid struct {
+			Items []lineItem `body:"items,json,stream"`
+		}
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(`[]`))
+		assert.ErrorContains(t, Decode(r, pathParams(nil), &invalid{}), "stream body requires")
+	})
+}
+
+func TestDecoder_MaxBodyBytes(t *testing.T) {
+	type request struct {
+		Item lineItem `body:"item"`
+	}
+
+	decoder := NewDecoder(pathParams(nil), MaxBodyBytes(32))
+
+	r, _ := http.NewRequest("POST", "/", strings.NewReader(`{"sku":"a","qty":1}`))
+	var req request
+	assert.NoError(t, decoder.Decode(r, &req))
+	assert.Equal(t, lineItem{SKU: "a", Qty: 1}, req.Item)
+
+	r, _ = http.NewRequest("POST", "/", strings.NewReader(`{"sku":"`+strings.Repeat("a", 64)+`","qty":1}`))
+	err := decoder.Decode(r, &request{})
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	assert.ErrorContains(t, err, "limit is 32 bytes")
+}
+
+func TestDecoder_LenientNumbers(t *testing.T) {
+	type payload struct {
+		ID    string          `json:"id"`
+		Count int             `json:"count"`
+		Price *float64        `json:"price"`
+		Tags  []string        `json:"tags"`
+		Stock map[string]uint `json:"stock"`
+		Items []lineItem      `json:"items"`
+		When  time.Time       `json:"when"`
+	}
+	type request struct {
+		Payload payload `body:"payload"`
+	}
+	body := `{"id":123,"count":"5","price":"9.5","tags":[1,"b"],"stock":{"a":"3"},` +
+		`"items":[{"sku":42,"qty":"2"}],"when":"2024-01-02T00:00:00Z"}`
+
+	t.Run("strict by default", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(`{"id":123}`))
+		assert.Error(t, Decode(r, pathParams(nil), &request{}))
+
+		r, _ = http.NewRequest("POST", "/", strings.NewReader(`{"count":"5"}`))
+		assert.Error(t, Decode(r, pathParams(nil), &request{}))
+	})
+
+	t.Run("coerces both ways", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		var req request
+		assert.NoError(t, NewDecoder(pathParams(nil), LenientNumbers()).Decode(r, &req))
+
+		price := 9.5
+		assert.Equal(t, payload{
+			ID:    "123",
+			Count: 5,
+			Price: &price,
+			Tags:  []string{"1", "b"},
+			Stock: map[string]uint{"a": 3},
+			Items: []lineItem{{SKU: "42", Qty: 2}},
+			When:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		}, req.Payload)
+	})
+
+	t.Run("non numeric string", func(t *testing.T) {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(`{"count":"five"}`))
+		assert.Error(t, NewDecoder(pathParams(nil), LenientNumbers()).Decode(r, &request{}))
+	})
+}
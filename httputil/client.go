@@ -23,18 +23,29 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+
+	liberrors "github.com/enverbisevac/libs/errors"
 )
 
+// ErrResponseTooLarge is returned when response body exceeds the limit
+// set by WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
 type Client struct {
-	client *http.Client
-	base   string
-	debug  bool
+	client           *http.Client
+	base             string
+	debug            bool
+	maxResponseBytes int64
+	retryPolicy      *RetryPolicy
+	typedErrors      bool
 }
 
 func NewClient(uri string, options ...ClientOption) *Client {
@@ -75,6 +86,11 @@ func (c *Client) Post(ctx context.Context, rawurl string, in, out any, options .
 	return c.do(ctx, rawurl, http.MethodPost, in, out, options...)
 }
 
+// helper function for making an http PUT request.
+func (c *Client) Put(ctx context.Context, rawurl string, in, out any, options ...RequestOption) error {
+	return c.do(ctx, rawurl, http.MethodPut, in, out, options...)
+}
+
 // helper function for making an http PATCH request.
 func (c *Client) Patch(ctx context.Context, rawurl string, in, out any, options ...RequestOption) error {
 	return c.do(ctx, rawurl, http.MethodPatch, in, out, options...)
@@ -85,30 +101,137 @@ func (c *Client) Delete(ctx context.Context, rawurl string, options ...RequestOp
 	return c.do(ctx, rawurl, http.MethodDelete, nil, nil, options...)
 }
 
-// helper function to make an http request.
-func (c *Client) do(ctx context.Context, rawurl, method string, in, out any, options ...RequestOption) error {
-	// executes the http request and returns the body as
-	// and io.ReadCloser
-	body, err := c.stream(ctx, rawurl, method, in, options...)
-	if body != nil {
-		defer func(body io.ReadCloser) {
-			_ = body.Close()
-		}(body)
+// Request sends req to path with the given method and decodes the JSON
+// response into out. Fields of req are encoded the way Decode reads
+// them: path tagged fields replace {name} segments of path, query
+// tagged fields are sent as query parameters and the body tagged
+// field is sent as the JSON payload.
+//
+//	type GetArticle struct {
+//		ID     string   `path:"id"`
+//		Fields []string `query:"fields"`
+//	}
+//	err := client.Request(ctx, http.MethodGet, "/articles/{id}", GetArticle{ID: "1"}, &article)
+func (c *Client) Request(ctx context.Context, method, path string, req, out any, options ...RequestOption) error {
+	if req == nil {
+		return c.do(ctx, path, method, nil, out, options...)
+	}
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
 	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("invalid request type: %v", v.Kind())
+	}
+
+	path, in, err := expandRequest(path, v)
+	if err != nil {
+		return err
+	}
+	query, err := Encode(req)
 	if err != nil {
 		return err
 	}
+	if len(query) > 0 {
+		options = append([]RequestOption{RequestOptionFunc(func(r *http.Request) {
+			r.URL.RawQuery = query.Encode()
+		})}, options...)
+	}
+	if strings.Contains(path, "{") {
+		return fmt.Errorf("missing path params in %s", path)
+	}
+	return c.do(ctx, path, method, in, out, options...)
+}
+
+// expandRequest replaces {name} segments of path with path tagged
+// fields of v and returns the value of body tagged field.
+func expandRequest(path string, v reflect.Value) (string, any, error) {
+	var body any
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isNested(field, parseFieldConf(field.Tag.Get(InQuery))) {
+			var (
+				nested any
+				err    error
+			)
+			if path, nested, err = expandRequest(path, v.Field(i)); err != nil {
+				return "", nil, err
+			}
+			if nested != nil {
+				body = nested
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if tag := field.Tag.Get(InPath); tag != "" {
+			conf := parseFieldConf(tag)
+			value, err := formatValue(v.Field(i))
+			if err != nil {
+				return "", nil, fmt.Errorf("path %s: %w", conf.name, err)
+			}
+			path = strings.ReplaceAll(path, "{"+conf.name+"}", url.PathEscape(value))
+		}
+		if field.Tag.Get("body") != "" && !isEmptyValue(v.Field(i)) {
+			body = v.Field(i).Interface()
+		}
+	}
+	return path, body, nil
+}
+
+// helper function to make an http request.
+func (c *Client) do(ctx context.Context, rawurl, method string, in, out any, options ...RequestOption) error {
+	_, err := c.DoResponse(ctx, method, rawurl, in, out, options...)
+	return err
+}
+
+// Response holds status code and headers of a response, returned by
+// DoResponse.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+}
+
+// DoResponse makes an http request same as Get, Post and other helper
+// methods and returns status code and headers of the response, useful
+// for reading ETag or Location or telling 201 from 200.
+func (c *Client) DoResponse(ctx context.Context, method, rawurl string, in, out any, options ...RequestOption) (*Response, error) {
+	resp, err := c.send(ctx, rawurl, method, in, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(resp.Body)
+
+	res := &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
 
 	// if a json response is expected, parse and return
 	// the json response.
 	if out != nil {
-		return json.NewDecoder(body).Decode(out)
+		return res, c.limitErr(json.NewDecoder(resp.Body).Decode(out))
+	}
+	return res, nil
+}
+
+// limitErr replaces error returned when the response body limit is
+// exceeded with ErrResponseTooLarge.
+func (c *Client) limitErr(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, maxErr.Limit)
 	}
-	return nil
+	return err
 }
 
-// helper function to stream a http request.
-func (c *Client) stream(ctx context.Context, rawurl, method string, in any, options ...RequestOption) (io.ReadCloser, error) {
+// helper function to send a http request, response body is closed
+// when the response status is an error.
+func (c *Client) send(ctx context.Context, rawurl, method string, in any, options ...RequestOption) (*http.Response, error) {
 	uri, err := url.JoinPath(c.base, rawurl)
 	if err != nil {
 		return nil, err
@@ -116,7 +239,7 @@ func (c *Client) stream(ctx context.Context, rawurl, method string, in any, opti
 
 	// if we are posting or putting data, we need to
 	// write it to the body of the request.
-	var buf io.ReadWriter
+	var buf *bytes.Buffer
 	if in != nil {
 		buf = &bytes.Buffer{}
 		// if posting form data, encode the form values.
@@ -129,37 +252,60 @@ func (c *Client) stream(ctx context.Context, rawurl, method string, in any, opti
 		}
 	}
 
-	// creates a new http request.
-	req, err := http.NewRequestWithContext(ctx, method, uri, buf)
-	if err != nil {
-		return nil, err
-	}
+	// creates a new http request, the body is buffered so the
+	// request can be rebuilt for every attempt.
+	newRequest := func() (*http.Request, error) {
+		var body io.Reader
+		if buf != nil {
+			body = bytes.NewReader(buf.Bytes())
+		}
+		req, err := http.NewRequestWithContext(ctx, method, uri, body)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, opt := range options {
-		opt.Apply(req)
-	}
+		for _, opt := range options {
+			opt.Apply(req)
+		}
 
-	if in != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
 
-	if _, ok := in.(*url.Values); ok {
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if _, ok := in.(*url.Values); ok {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		return req, nil
 	}
 
 	// send the http request.
-	resp, err := c.client.Do(req)
+	resp, err := c.retry(ctx, newRequest)
 	if err != nil {
 		return nil, err
 	}
 
+	if c.maxResponseBytes > 0 {
+		resp.Body = http.MaxBytesReader(nil, resp.Body, c.maxResponseBytes)
+	}
+
 	if resp.StatusCode >= http.StatusMultipleChoices {
 		defer func(Body io.ReadCloser) {
 			_ = Body.Close()
 		}(resp.Body)
 
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, c.limitErr(err)
+		}
+
+		if c.typedErrors {
+			if err := typedError(resp.StatusCode, data); err != nil {
+				return nil, err
+			}
+		}
+
 		errorResponse := ErrorResponse{}
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&errorResponse); decodeErr != nil {
+		if decodeErr := json.Unmarshal(data, &errorResponse); decodeErr != nil {
 			return nil, decodeErr
 		}
 
@@ -168,9 +314,21 @@ func (c *Client) stream(ctx context.Context, rawurl, method string, in any, opti
 			message = "Empty response from server."
 		}
 
+		errorResponse.Status = resp.StatusCode
 		return nil, errorResponse
 	}
-	return resp.Body, nil
+	return resp, nil
+}
+
+// typedError returns error decoded from errors.HttpResponse body or
+// nil when data is not in that shape.
+func typedError(status int, data []byte) error {
+	var response liberrors.HttpResponse
+	if err := json.Unmarshal(data, &response); err != nil || response.Msg == "" {
+		return nil
+	}
+	response.Status = status
+	return response.AsError()
 }
 
 type ErrorResponse struct {
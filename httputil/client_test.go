@@ -0,0 +1,284 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	liberrors "github.com/enverbisevac/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_MaxResponseBytes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"` + strings.Repeat("a", 1024) + `"}`))
+	}))
+	defer srv.Close()
+
+	var out struct {
+		Name string `json:"name"`
+	}
+
+	client := NewClient(srv.URL, WithMaxResponseBytes(100))
+	err := client.Get(context.Background(), "/", &out)
+	assert.True(t, errors.Is(err, ErrResponseTooLarge), "got: %v", err)
+
+	client = NewClient(srv.URL, WithMaxResponseBytes(2048))
+	assert.NoError(t, client.Get(context.Background(), "/", &out))
+	assert.Len(t, out.Name, 1024)
+}
+
+func TestClient_Request(t *testing.T) {
+	type article struct {
+		Title string `json:"title"`
+	}
+	type updateArticle struct {
+		ID      string   `path:"id"`
+		Fields  []string `query:"fields"`
+		DryRun  bool     `query:"dry_run,omitempty"`
+		Article *article `body:"article"`
+	}
+
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"title":"updated"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+
+	t.Run("path, query and body", func(t *testing.T) {
+		var out article
+		err := client.Request(context.Background(), http.MethodPatch, "/articles/{id}", updateArticle{
+			ID:      "a/1",
+			Fields:  []string{"title", "body"},
+			Article: &article{Title: "draft"},
+		}, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, article{Title: "updated"}, out)
+		assert.Equal(t, http.MethodPatch, got.Method)
+		assert.Equal(t, "/articles/a%2F1", got.URL.EscapedPath())
+		assert.Equal(t, "fields=title%2Cbody", got.URL.RawQuery)
+		assert.JSONEq(t, `{"title":"draft"}`, body)
+		assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	})
+
+	t.Run("round trips through Decode", func(t *testing.T) {
+		req := &updateArticle{ID: "7", Fields: []string{"title"}, DryRun: true, Article: &article{Title: "x"}}
+		assert.NoError(t, client.Request(context.Background(), http.MethodPatch, "/articles/{id}", req, nil))
+
+		got.Body = io.NopCloser(strings.NewReader(body))
+		var decoded updateArticle
+		assert.NoError(t, Decode(got, pathParams(map[string]string{"id": "7"}), &decoded))
+		assert.Equal(t, *req, decoded)
+	})
+
+	t.Run("no body field", func(t *testing.T) {
+		type listArticles struct {
+			Status string `query:"status"`
+		}
+		assert.NoError(t, client.Request(context.Background(), http.MethodGet, "/articles", listArticles{Status: "open"}, nil))
+		assert.Equal(t, "status=open", got.URL.RawQuery)
+		assert.Empty(t, body)
+		assert.Empty(t, got.Header.Get("Content-Type"))
+	})
+
+	t.Run("missing path param", func(t *testing.T) {
+		err := client.Request(context.Background(), http.MethodGet, "/articles/{slug}", updateArticle{ID: "1"}, nil)
+		assert.ErrorContains(t, err, "missing path params")
+	})
+}
+
+func TestClient_DoResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/articles/1")
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	resp, err := NewClient(srv.URL).DoResponse(context.Background(), http.MethodPost, "/articles", map[string]string{"title": "a"}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "/articles/1", resp.Header.Get("Location"))
+	assert.Equal(t, `"v1"`, resp.Header.Get("ETag"))
+	assert.Equal(t, "1", out.ID)
+}
+
+func TestClient_Retry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+
+	t.Run("retries transient failures with body", func(t *testing.T) {
+		var bodies []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"1"}`))
+		}))
+		defer srv.Close()
+
+		var out struct {
+			ID string `json:"id"`
+		}
+		err := NewClient(srv.URL, WithRetry(policy)).Post(context.Background(), "/", map[string]string{"a": "b"}, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", out.ID)
+		assert.Len(t, bodies, 3)
+		for _, body := range bodies {
+			assert.JSONEq(t, `{"a":"b"}`, body)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"Status":502}`))
+		}))
+		defer srv.Close()
+
+		err := NewClient(srv.URL, WithRetry(policy)).Get(context.Background(), "/", nil)
+		assert.Equal(t, ErrorResponse{Status: http.StatusBadGateway}, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer srv.Close()
+
+		assert.Error(t, NewClient(srv.URL, WithRetry(policy)).Get(context.Background(), "/", nil))
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("custom retryable and context cancel", func(t *testing.T) {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		client := NewClient(srv.URL, WithRetry(RetryPolicy{
+			MaxAttempts: 5,
+			Backoff:     time.Hour,
+			Retryable: func(resp *http.Response, err error) bool {
+				cancel()
+				return err == nil && resp.StatusCode == http.StatusInternalServerError
+			},
+		}))
+		err := client.Get(ctx, "/", nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	assert.Equal(t, 100*time.Millisecond, policy.delay(1))
+	assert.Equal(t, 200*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 800*time.Millisecond, policy.delay(4))
+	assert.Equal(t, time.Second, policy.delay(5))
+	assert.Equal(t, time.Second, policy.delay(100))
+
+	policy.Jitter = 0.5
+	for attempt := 5; attempt < 10; attempt++ {
+		assert.LessOrEqual(t, policy.delay(attempt), time.Second)
+		assert.GreaterOrEqual(t, policy.delay(attempt), 500*time.Millisecond)
+	}
+
+	policy = RetryPolicy{Backoff: time.Second}
+	assert.Equal(t, DefaultMaxBackoff, policy.delay(1000))
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			_ = liberrors.JSONResponse(w, liberrors.NotFound("article %s not found", "1"))
+		case "/conflict":
+			_ = liberrors.JSONResponse(w, liberrors.Conflict("article already exists"))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"Status":502,"Payload":"upstream"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithTypedErrors())
+
+	err := client.Get(context.Background(), "/missing", nil)
+	assert.True(t, liberrors.IsNotFound(err), "got: %#v", err)
+	assert.EqualError(t, err, "article 1 not found")
+
+	err = client.Get(context.Background(), "/conflict", nil)
+	assert.True(t, liberrors.IsConflict(err), "got: %#v", err)
+
+	err = client.Get(context.Background(), "/gateway", nil)
+	assert.Equal(t, ErrorResponse{Status: http.StatusBadGateway, Payload: "upstream"}, err)
+
+	err = NewClient(srv.URL).Get(context.Background(), "/missing", nil)
+	assert.IsType(t, ErrorResponse{}, err)
+}
+
+func TestClient_ErrorResponseStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"Payload":"article not found"}`))
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).Get(context.Background(), "/articles/1", nil)
+	assert.Equal(t, ErrorResponse{Status: http.StatusNotFound, Payload: "article not found"}, err)
+	assert.EqualError(t, err, "error occurred with status code 404 and payload article not found")
+}
+
+func TestClient_Put(t *testing.T) {
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		_, _ = w.Write([]byte(`{"title":"replaced"}`))
+	}))
+	defer srv.Close()
+
+	var out struct {
+		Title string `json:"title"`
+	}
+	err := NewClient(srv.URL).Put(context.Background(), "/articles/1", map[string]string{"title": "new"}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPut, got.Method)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"new"}`, body)
+	assert.Equal(t, "replaced", out.Title)
+}
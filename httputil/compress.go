@@ -0,0 +1,193 @@
+package httputil
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompressTypes are content types compressed by
+// CompressMiddleware when no types are given.
+var DefaultCompressTypes = []string{
+	"text/*",
+	"application/json",
+	"application/problem+json",
+	"application/xml",
+	"application/yaml",
+	"application/javascript",
+	"image/svg+xml",
+}
+
+// CompressMiddleware compresses responses with gzip when the client
+// accepts it and the response content type is one of types, which may
+// contain type/* wildcards. DefaultCompressTypes are used when no types
+// are given. Responses which already have Content-Encoding set are
+// not compressed. Vary: Accept-Encoding is set on every response, so
+// caches keep compressed and plain variants apart. Invalid level is
+// replaced by gzip.DefaultCompression.
+//
+//	chain := httputil.NewChain(httputil.CompressMiddleware(gzip.BestSpeed))
+func CompressMiddleware(level int, types ...string) Constructor {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	if len(types) == 0 {
+		types = DefaultCompressTypes
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addVary(w.Header(), "Accept-Encoding")
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{
+				ResponseWriter: w,
+				level:          level,
+				types:          types,
+			}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptsGzip reports whether Accept-Encoding header value accepts
+// gzip. Explicit gzip entry takes precedence over the * wildcard, so
+// "gzip;q=0, *" does not accept gzip.
+func acceptsGzip(header string) bool {
+	gzip, wildcard := -1, -1
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		accepted := 1
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(v, 64); err != nil || q <= 0 {
+				accepted = 0
+			}
+		}
+		if coding == "gzip" {
+			gzip = accepted
+		} else {
+			wildcard = accepted
+		}
+	}
+	if gzip >= 0 {
+		return gzip == 1
+	}
+	return wildcard == 1
+}
+
+// addVary adds field to the Vary header unless it is already listed.
+func addVary(header http.Header, field string) {
+	for _, v := range header.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f == "*" || strings.EqualFold(f, field) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", field)
+}
+
+// compressWriter decides whether to compress when the first part of
+// the body is written, when the content type is known.
+type compressWriter struct {
+	http.ResponseWriter
+	level       int
+	types       []string
+	gz          *gzip.Writer
+	status      int
+	wroteHeader bool
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.wroteHeader || w.status != 0 {
+		return
+	}
+	w.status = status
+	// responses without body are written as they are.
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.writeHeader(nil)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.writeHeader(b)
+	}
+	if w.gz != nil {
+		return w.gz.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// writeHeader writes the response header, compressing the body when
+// its content type, detected from b if not set, is allowed.
+func (w *compressWriter) writeHeader(b []byte) {
+	w.wroteHeader = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	header := w.Header()
+	if b != nil && header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(b))
+	}
+	if b != nil && header.Get("Content-Encoding") == "" && w.compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz, _ = gzip.NewWriterLevel(w.ResponseWriter, w.level)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// compressible reports whether contentType matches one of the types.
+func (w *compressWriter) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range w.types {
+		if prefix, ok := strings.CutSuffix(t, "*"); ok && strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// Flush flushes compressed data and the underlying writer. The header
+// is written on the first flush only when the content type is set.
+func (w *compressWriter) Flush() {
+	if !w.wroteHeader && w.Header().Get("Content-Type") != "" {
+		w.writeHeader([]byte{})
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close writes pending header and completes the gzip stream.
+func (w *compressWriter) close() {
+	if !w.wroteHeader && w.status != 0 {
+		w.writeHeader(nil)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
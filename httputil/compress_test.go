@@ -0,0 +1,106 @@
+package httputil
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressMiddleware(t *testing.T) {
+	body := `{"items":[` + strings.Repeat(`{"name":"article"},`, 100) + `{}]}`
+	handler := NewChain(CompressMiddleware(gzip.BestSpeed)).ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+		case "/encoded":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "br")
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+			return
+		default:
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, body[:10])
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, body[10:])
+	})
+
+	serve := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		if acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("json is gzipped", func(t *testing.T) {
+		w := serve("/articles", "br, gzip;q=0.8")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, []string{"Accept-Encoding"}, w.Header().Values("Vary"))
+		assert.True(t, w.Flushed)
+		assert.Less(t, w.Body.Len(), len(body))
+
+		gz, err := gzip.NewReader(w.Body)
+		assert.NoError(t, err)
+		got, err := io.ReadAll(gz)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(got))
+	})
+
+	tests := []struct {
+		name, path, acceptEncoding string
+	}{
+		{"gzip not accepted", "/articles", ""},
+		{"gzip refused", "/articles", "gzip;q=0, br"},
+		{"gzip refused before wildcard", "/articles", "gzip;q=0, *"},
+		{"gzip refused after wildcard", "/articles", "*, gzip;q=0"},
+		{"type not allowed", "/image", "gzip"},
+		{"already encoded", "/encoded", "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.path, tt.acceptEncoding)
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.NotEqual(t, "gzip", w.Header().Get("Content-Encoding"))
+			assert.Equal(t, []string{"Accept-Encoding"}, w.Header().Values("Vary"))
+			assert.Equal(t, body, w.Body.String())
+		})
+	}
+
+	t.Run("wildcard accepts gzip", func(t *testing.T) {
+		w := serve("/articles", "br;q=0.5, *")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		w := serve("/empty", "gzip")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, []string{"Accept-Encoding"}, w.Header().Values("Vary"))
+		assert.Zero(t, w.Body.Len())
+	})
+
+	t.Run("vary is not duplicated", func(t *testing.T) {
+		handler := NewChain(CompressMiddleware(gzip.BestSpeed)).ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, body)
+		})
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		w.Header().Set("Vary", "Origin, accept-encoding")
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, []string{"Origin, accept-encoding"}, w.Header().Values("Vary"))
+	})
+}
@@ -0,0 +1,143 @@
+package httputil
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	liberrors "github.com/enverbisevac/libs/errors"
+)
+
+var (
+	// BeforeVar specifies the query parameter name for the cursor of
+	// the previous page
+	BeforeVar = "before"
+	// AfterVar specifies the query parameter name for the cursor of
+	// the next page
+	AfterVar = "after"
+	// LimitVar specifies the query parameter name for cursor page size
+	LimitVar = "limit"
+)
+
+// Cursor represents a keyset paginated list of data items. Before and
+// After are opaque tokens of the request, Next and Prev are tokens of
+// the adjacent pages.
+type Cursor[T any] struct {
+	Before string `json:"-"`
+	After  string `json:"-"`
+	Limit  int    `json:"limit"`
+	Next   string `json:"next,omitempty"`
+	Prev   string `json:"prev,omitempty"`
+	Items  []T    `json:"items"`
+}
+
+// CursorFromRequest creates a Cursor object using the before, after
+// and limit query parameters found in the given HTTP request. Limit
+// defaults to DefaultPageSize and is capped at MaxPageSize.
+func CursorFromRequest[T any](req *http.Request) *Cursor[T] {
+	limit := QueryParamOrDefault(req, LimitVar, DefaultPageSize)
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	query := req.URL.Query()
+	return &Cursor[T]{
+		Before: query.Get(BeforeVar),
+		After:  query.Get(AfterVar),
+		Limit:  limit,
+	}
+}
+
+// EncodeCursor returns opaque cursor token of key, key is encoded as
+// JSON so it can be any value which sorts the items, for example
+// struct holding creation time and id.
+func EncodeCursor(key any) (string, error) {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes token created by EncodeCursor into key. Invalid
+// tokens return a validation error.
+func DecodeCursor(token string, key any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(data, key)
+	}
+	if err != nil {
+		return liberrors.Validation("invalid cursor %q", token)
+	}
+	return nil
+}
+
+// SetItems sets items of the page and the Next and Prev tokens from the
+// keys of the last and first item. A full page is assumed to have more
+// items in the paging direction.
+func (c *Cursor[T]) SetItems(items []T, key func(item T) any) error {
+	c.Items = items
+	c.Next, c.Prev = "", ""
+	if len(items) == 0 {
+		return nil
+	}
+
+	full := len(items) >= c.Limit
+	hasNext, hasPrev := full, c.After != ""
+	if c.Before != "" {
+		hasNext, hasPrev = true, full
+	}
+
+	var err error
+	if hasNext {
+		if c.Next, err = EncodeCursor(key(items[len(items)-1])); err != nil {
+			return err
+		}
+	}
+	if hasPrev {
+		if c.Prev, err = EncodeCursor(key(items[0])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildCursorLinks returns the next and prev links carrying the cursor
+// tokens. A link is an empty string if there is no such page.
+func (c *Cursor[T]) BuildCursorLinks(baseURL string) (next, prev string) {
+	if strings.Contains(baseURL, "?") {
+		baseURL += "&"
+	} else {
+		baseURL += "?"
+	}
+	limit := ""
+	if c.Limit != DefaultPageSize {
+		limit = fmt.Sprintf("&%v=%v", LimitVar, c.Limit)
+	}
+	if c.Next != "" {
+		next = fmt.Sprintf("%v%v=%v%v", baseURL, AfterVar, url.QueryEscape(c.Next), limit)
+	}
+	if c.Prev != "" {
+		prev = fmt.Sprintf("%v%v=%v%v", baseURL, BeforeVar, url.QueryEscape(c.Prev), limit)
+	}
+	return next, prev
+}
+
+// BuildLinkHeader returns an HTTP header containing the next and prev
+// links of the cursor.
+func (c *Cursor[T]) BuildLinkHeader(baseURL string) string {
+	next, prev := c.BuildCursorLinks(baseURL)
+	var links []string
+	if prev != "" {
+		links = append(links, fmt.Sprintf("<%v>; rel=\"prev\"", prev))
+	}
+	if next != "" {
+		links = append(links, fmt.Sprintf("<%v>; rel=\"next\"", next))
+	}
+	return strings.Join(links, ", ")
+}
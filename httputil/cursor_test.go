@@ -0,0 +1,72 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/enverbisevac/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type articleKey struct {
+	Created time.Time `json:"c"`
+	ID      int       `json:"i"`
+}
+
+func TestEncodeCursor(t *testing.T) {
+	key := articleKey{Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), ID: 42}
+	token, err := EncodeCursor(key)
+	assert.NoError(t, err)
+	assert.NotContains(t, token, "=")
+
+	var got articleKey
+	assert.NoError(t, DecodeCursor(token, &got))
+	assert.Equal(t, key, got)
+
+	err = DecodeCursor("not a cursor!", &got)
+	assert.True(t, errors.IsValidation(err), "got: %v", err)
+}
+
+func TestCursorFromRequest(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/articles?after=abc&limit=5000", nil)
+	c := CursorFromRequest[int](r)
+	assert.Equal(t, &Cursor[int]{After: "abc", Limit: MaxPageSize}, c)
+
+	r, _ = http.NewRequest("GET", "/articles?before=xyz&limit=-1", nil)
+	c = CursorFromRequest[int](r)
+	assert.Equal(t, &Cursor[int]{Before: "xyz", Limit: DefaultPageSize}, c)
+}
+
+func TestCursor_BuildLinkHeader(t *testing.T) {
+	key := func(item int) any { return item }
+	token := func(key int) string {
+		s, _ := EncodeCursor(key)
+		return s
+	}
+
+	tests := []struct {
+		tag    string
+		url    string
+		items  []int
+		header string
+	}{
+		{"first page", "/articles?limit=3", []int{1, 2, 3},
+			`</articles?after=` + token(3) + `&limit=3>; rel="next"`},
+		{"last page", "/articles?limit=3&after=" + token(3), []int{4, 5},
+			`</articles?before=` + token(4) + `&limit=3>; rel="prev"`},
+		{"middle page", "/articles?after=" + token(3), []int{4},
+			`</articles?before=` + token(4) + `>; rel="prev"`},
+		{"backwards", "/articles?limit=2&before=" + token(4), []int{2, 3},
+			`</articles?before=` + token(2) + `&limit=2>; rel="prev", </articles?after=` + token(3) + `&limit=2>; rel="next"`},
+		{"empty", "/articles", nil, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", test.url, nil)
+			c := CursorFromRequest[int](r)
+			assert.NoError(t, c.SetItems(test.items, key))
+			assert.Equal(t, test.header, c.BuildLinkHeader("/articles"))
+		})
+	}
+}
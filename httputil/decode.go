@@ -1,17 +1,101 @@
 package httputil
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
 )
 
+// ErrUnknownQueryParam is returned by a strict Decoder when the request
+// contains query parameters which are not mapped to any field.
+var ErrUnknownQueryParam = errors.New("unknown query parameter")
+
+// ErrRequiredParam is returned when a parameter tagged as required is
+// missing from the request.
+var ErrRequiredParam = errors.New("required parameter is missing")
+
 type RequestURLParam func(r *http.Request, key string) string
 
+// Decoder decodes HTTP request path, query and header values into
+// the tagged fields of a struct.
+type Decoder struct {
+	pathValue            RequestURLParam
+	rejectUnknownQuery   bool
+	snakeCaseQuery       bool
+	caseInsensitiveQuery bool
+	requireBody          bool
+	lenientNumbers       bool
+	flagQuery            bool
+	maxBodyBytes         int64
+	// bodyDecoders and contentTypes extend the default body formats.
+	bodyDecoders map[string]BodyDecoder
+	contentTypes map[string]string
+}
+
+// NewDecoder creates a new Decoder, fn is used for reading path values
+// from the request (for example chi.URLParam).
+func NewDecoder(fn RequestURLParam, options ...DecoderOption) *Decoder {
+	d := &Decoder{
+		pathValue: fn,
+	}
+
+	for _, opt := range options {
+		opt.Apply(d)
+	}
+
+	return d
+}
+
+// With returns a copy of d with options applied, so a shared decoder
+// can be specialized per route without changing it:
+//
+//	strict := decoder.With(httputil.RejectUnknownQuery())
+func (d *Decoder) With(options ...DecoderOption) *Decoder {
+	c := *d
+	c.bodyDecoders = maps.Clone(d.bodyDecoders)
+	c.contentTypes = maps.Clone(d.contentTypes)
+
+	for _, opt := range options {
+		opt.Apply(&c)
+	}
+
+	return &c
+}
+
 // Decode an HTTP request into the provided struct
 func Decode(r *http.Request, fn RequestURLParam, data interface{}) error {
+	return NewDecoder(fn).Decode(r, data)
+}
+
+// Decode an HTTP request into the provided struct
+func (d *Decoder) Decode(r *http.Request, data interface{}) error {
+	return d.decode(r, data, nil)
+}
+
+// DecodeWithMeta decodes an HTTP request into the provided struct same
+// as Decode and returns FieldMask of fields which had a value in the
+// request. Fields bound from missing query parameters, headers, path
+// values or empty body are not in the mask, so PATCH handlers can
+// distinguish zero value from not provided one.
+func (d *Decoder) DecodeWithMeta(r *http.Request, data interface{}) (FieldMask, error) {
+	mask := make(FieldMask)
+	if err := d.decode(r, data, mask); err != nil {
+		return nil, err
+	}
+	return mask, nil
+}
+
+func (d *Decoder) decode(r *http.Request, data interface{}, mask FieldMask) error {
 	typ := reflect.TypeOf(data)
 	if typ == nil {
 		return fmt.Errorf("invalid decode type: nil")
@@ -19,20 +103,140 @@ func Decode(r *http.Request, fn RequestURLParam, data interface{}) error {
 	if typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
 	}
+	if typ.Kind() == reflect.Slice && reflect.TypeOf(data).Kind() == reflect.Ptr {
+		// top level arrays are bound from the body
+		return d.decodeBody(r, "", data)
+	}
 	if typ.Kind() != reflect.Struct {
 		return fmt.Errorf("invalid decode type: %v", typ.Kind())
 	}
 
-	return decodeRequest(r, typ, func(key string) string {
-		return fn(r, key)
-	}, data)
+	query := r.URL.Query()
+	if d.caseInsensitiveQuery {
+		query = foldQuery(r.URL.RawQuery, paramsOf(typ))
+	}
+
+	s := &decodeState{
+		Decoder: d,
+		r:       r,
+		query:   query,
+		path: func(key string) string {
+			if d.pathValue == nil {
+				return ""
+			}
+			return d.pathValue(r, key)
+		},
+		known: make(map[string]struct{}),
+		mask:  mask,
+	}
+
+	return s.decodeRequest(typ, data)
 }
 
-func decodeRequest(r *http.Request, t reflect.Type, fn URLParam, data interface{}) error {
-	_, err := decodeStruct(r, t, fn, data)
+// DecodeWithDefaults decodes an HTTP request into the provided struct
+// same as Decode, fields which had no value in the request are set from
+// defaults, which must be a pointer to the struct of the same type.
+// Values are copied shallowly, so pointers, slices and maps are shared
+// with defaults:
+//
+//	defaults := ListArticles{Limit: 20, Sort: "-created"}
+//	var req ListArticles
+//	err := decoder.DecodeWithDefaults(r, &req, &defaults)
+func (d *Decoder) DecodeWithDefaults(r *http.Request, data, defaults interface{}) error {
+	if reflect.TypeOf(data) != reflect.TypeOf(defaults) {
+		return fmt.Errorf("defaults type %T does not match %T", defaults, data)
+	}
+	mask, err := d.DecodeWithMeta(r, data)
 	if err != nil {
 		return err
 	}
+	dst := reflect.ValueOf(data).Elem()
+	if dst.Kind() != reflect.Struct {
+		return nil
+	}
+	applyDefaults(dst, reflect.ValueOf(defaults).Elem(), "", mask)
+	return nil
+}
+
+// applyDefaults sets fields of dst which are not in mask from src.
+func applyDefaults(dst, src reflect.Value, prefix string, mask FieldMask) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		path := field.Name
+		if prefix != "" {
+			path = prefix + "." + field.Name
+		}
+		// fields present in the request are leaves, body fields are
+		// decoded as a whole and never merged with defaults
+		if mask.Has(path) {
+			continue
+		}
+		_, body := field.Tag.Lookup("body")
+		if !body && isNested(field, parseFieldConf(field.Tag.Get("query"))) {
+			nested := path
+			if field.Anonymous {
+				nested = prefix
+			}
+			applyDefaults(dst.Field(i), src.Field(i), nested, mask)
+			continue
+		}
+		dst.Field(i).Set(src.Field(i))
+	}
+}
+
+// isNested reports whether fields of the struct field are decoded as
+// fields of the request.
+func isNested(field reflect.StructField, conf fieldConf) bool {
+	return field.Type.Kind() == reflect.Struct && field.IsExported() && !conf.deepObject &&
+		field.Type != reflect.TypeOf(time.Time{}) && !isSetter(field.Type)
+}
+
+// decodeState holds the state of a single Decode call.
+type decodeState struct {
+	*Decoder
+	r     *http.Request
+	query url.Values
+	path  URLParam
+	// known contains all query parameter names mapped to fields.
+	known map[string]struct{}
+	// errs collects path, query, header and cookie field failures.
+	errs []FieldError
+	// mask collects paths of fields present in the request, it is nil
+	// when not requested.
+	mask FieldMask
+}
+
+// setField records field path in the mask when present is true.
+func (s *decodeState) setField(path string, present bool) {
+	if s.mask != nil && present {
+		s.mask[path] = struct{}{}
+	}
+}
+
+// addFieldError records err of the field when it is not nil.
+func (s *decodeState) addFieldError(tag, name string, err error) {
+	if err != nil {
+		s.errs = append(s.errs, FieldError{Tag: tag, Name: name, Err: err})
+	}
+}
+
+func (s *decodeState) decodeRequest(t reflect.Type, data interface{}) error {
+	_, err := s.decodeStruct(t, data, "")
+	if err != nil {
+		return err
+	}
+	if len(s.errs) > 0 {
+		return &DecodeError{Fields: s.errs}
+	}
+	if s.rejectUnknownQuery {
+		if err := s.checkUnknownQuery(); err != nil {
+			return err
+		}
+	}
 	// if !body {
 	// 	err := decodeBody(r, data)
 	// 	if err != nil {
@@ -42,65 +246,189 @@ func decodeRequest(r *http.Request, t reflect.Type, fn URLParam, data interface{
 	return nil
 }
 
-func decodeStruct(r *http.Request, t reflect.Type, fn URLParam, data interface{}) (bool, error) {
-	query := r.URL.Query()
+// decodeStruct decodes fields of struct t, prefix is the path of the
+// struct in the decoded value.
+func (s *decodeState) decodeStruct(t reflect.Type, data interface{}, prefix string) (bool, error) {
 	body := false
 	for i := 0; i < t.NumField(); i++ {
 		typ := t.Field(i)
 		field := reflect.ValueOf(data).Elem().Field(i)
+		path := typ.Name
+		if prefix != "" {
+			path = prefix + "." + typ.Name
+		}
 
-		if typ.Type.Kind() == reflect.Struct {
+		queryTag := typ.Tag.Get("query")
+		if queryTag == "" && s.snakeCaseQuery && isUntaggedValue(typ) {
+			queryTag = snakeCase(typ.Name)
+		}
+		conf := parseFieldConf(queryTag)
+		if queryTag != "" {
 			var err error
-			if body, err = decodeStruct(r, typ.Type, fn, field.Addr().Interface()); err != nil {
+			if conf, err = queryFieldConf(typ.Type, queryTag); err != nil {
 				return body, err
 			}
 		}
+		conf.flag = s.flagQuery
 
-		if queryTag := typ.Tag.Get("query"); queryTag != "" {
-			if err := decodeQuery(field, typ.Type, query, queryTag); err != nil {
+		if isNested(typ, conf) {
+			var err error
+			nested := path
+			if typ.Anonymous {
+				nested = prefix
+			}
+			if body, err = s.decodeStruct(typ.Type, field.Addr().Interface(), nested); err != nil {
 				return body, err
 			}
 		}
 
-		if pathTag := typ.Tag.Get("path"); pathTag != "" {
-			if err := decodePath(field, typ.Type, fn, pathTag); err != nil {
-				return body, err
+		if queryTag != "" {
+			s.known[conf.name] = struct{}{}
+			var err error
+			if conf.deepObject {
+				present := len(deepValues(s.query, conf.name)) > 0
+				if conf.required && !present {
+					err = ErrRequiredParam
+				} else {
+					err = decodeDeepObject(field, typ.Type, s.query, conf.name)
+				}
+				s.setField(path, present)
+			} else {
+				err = decodeQuery(field, typ.Type, s.query, conf)
+				s.setField(path, s.query.Has(conf.name))
 			}
+			s.addFieldError(InQuery, conf.name, err)
+		}
+
+		if pathTag := typ.Tag.Get("path"); pathTag != "" {
+			conf := parseFieldConf(pathTag)
+			s.addFieldError(InPath, conf.name, decodePath(field, typ.Type, s.path, conf))
+			s.setField(path, s.path(conf.name) != "")
 		}
 
 		if headerTag := typ.Tag.Get("header"); headerTag != "" {
-			if err := decodeHeader(field, typ.Type, r.Header, headerTag); err != nil {
+			conf := parseFieldConf(headerTag)
+			s.addFieldError(InHeader, conf.name, decodeHeader(field, typ.Type, s.r.Header, conf))
+			s.setField(path, len(s.r.Header.Values(conf.name)) > 0 || (conf.prefix && field.Kind() == reflect.Map && field.Len() > 0))
+		}
+
+		if cookieTag, ok := typ.Tag.Lookup(InCookie); ok {
+			conf := parseFieldConf(cookieTag)
+			if conf.name == "" {
+				conf.name = strings.ToLower(typ.Name)
+			}
+			present, err := decodeCookie(field, typ.Type, s.r, conf)
+			s.addFieldError(InCookie, conf.name, err)
+			s.setField(path, present)
+		}
+
+		if metaTag := typ.Tag.Get("meta"); metaTag != "" {
+			value, err := metaValue(s.r, metaTag)
+			if err != nil {
 				return body, err
 			}
+			s.addFieldError("meta", metaTag, resolveValue(field, typ.Type, value))
+			s.setField(path, true)
 		}
 
-		// bodyTag := typ.Tag.Get("body")
-		// if bodyTag != "" {
-		// 	body = true
-		// 	if err := decodeBody(r, field.Addr().Interface()); err != nil {
-		// 		return body, err
-		// 	}
-		// }
+		if bodyTag := typ.Tag.Get("body"); bodyTag != "" {
+			body = true
+			conf := parseFieldConf(bodyTag)
+			if conf.stream {
+				if err := s.streamBody(s.r, conf.format, field); err != nil {
+					return body, err
+				}
+				s.setField(path, s.r.Body != nil && s.r.Body != http.NoBody)
+				continue
+			}
+			present, err := s.readBody(s.r, conf.format, field.Addr().Interface())
+			if err != nil {
+				return body, err
+			}
+			s.setField(path, present)
+		}
 	}
 	return body, nil
 }
 
-func decodeQuery(field reflect.Value, typ reflect.Type, query url.Values, tag string) error {
-	parts := strings.Split(tag, ",")
-	if query.Has(parts[0]) {
-		if field.Kind() == reflect.Slice {
-			var explode bool
-			for _, p := range parts[1:] {
-				if p == "explode" {
-					explode = true
-				}
-			}
+// foldQuery parses raw query, keys which match name of a query param
+// ignoring case are stored under the param name. Values of keys which
+// differ only in case are kept once, in request order.
+func foldQuery(raw string, params []Param) url.Values {
+	names := make(map[string]string)
+	for _, p := range params {
+		if p.In == InQuery {
+			names[strings.ToLower(p.Name)] = p.Name
+		}
+	}
+
+	query := url.Values{}
+	for _, pair := range strings.Split(raw, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			continue
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			continue
+		}
+		// deepObject keys are matched by the name in front of the bracket
+		name, rest := key, ""
+		if i := strings.IndexByte(key, '['); i > 0 {
+			name, rest = key[:i], key[i:]
+		}
+		if canonical, ok := names[strings.ToLower(name)]; ok {
+			key = canonical + rest
+		}
+		query[key] = append(query[key], value)
+	}
+	return query
+}
+
+// checkUnknownQuery returns an error listing all query parameters which
+// are not mapped to any field. Bracket keys like filter[status] or ids[]
+// are matched by the name in front of the first bracket.
+func (s *decodeState) checkUnknownQuery() error {
+	var unknown []string
+	for key := range s.query {
+		name := key
+		if i := strings.IndexByte(name, '['); i > 0 {
+			name = name[:i]
+		}
+		if _, ok := s.known[name]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("%w: %s", ErrUnknownQueryParam, strings.Join(unknown, ", "))
+}
 
+func decodeQuery(field reflect.Value, typ reflect.Type, query url.Values, conf fieldConf) error {
+	if conf.required && !query.Has(conf.name) {
+		// present but empty value satisfies required
+		return ErrRequiredParam
+	}
+	if conf.hasDefault && !query.Has(conf.name) {
+		field.Set(copyValue(conf.defaultField))
+		return nil
+	}
+	if query.Has(conf.name) {
+		if conf.base64 {
+			return decodeBase64(field, typ, query.Get(conf.name))
+		}
+		if field.Kind() == reflect.Slice && !isTextUnmarshaler(typ) {
 			var value []string
-			if explode {
-				value = query[parts[0]]
+			if conf.explode {
+				value = query[conf.name]
 			} else {
-				value = strings.Split(query.Get(parts[0]), ",")
+				value = strings.Split(query.Get(conf.name), ",")
 			}
 
 			if err := resolveValues(field, typ, value); err != nil {
@@ -108,17 +436,209 @@ func decodeQuery(field reflect.Value, typ reflect.Type, query url.Values, tag st
 			}
 			return nil
 		}
-		if err := resolveValue(field, typ, query.Get(parts[0])); err != nil {
+		value := query.Get(conf.name)
+		if conf.flag && value == "" && isBool(typ) {
+			value = "true"
+		}
+		if err := resolveValue(field, typ, value); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// isBool reports whether typ is bool or pointer to bool.
+func isBool(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Bool
+}
+
+// decodeBase64 decodes std or URL base64 encoded value, with or without
+// padding, into []byte field.
+func decodeBase64(field reflect.Value, typ reflect.Type, value string) error {
+	if typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Uint8 {
+		return fmt.Errorf("base64 requires []byte field, got %v", typ)
+	}
+	// unescaped + in query is decoded as space
+	value = strings.TrimRight(strings.ReplaceAll(value, " ", "+"), "=")
+	enc := base64.RawURLEncoding
+	if strings.ContainsAny(value, "+/") {
+		enc = base64.RawStdEncoding
+	}
+	b, err := enc.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("invalid base64 value: %w", err)
+	}
+	field.Set(reflect.ValueOf(b).Convert(typ))
+	return nil
+}
+
+// decodeDeepObject decodes query parameters in deepObject style, for
+// example filter[status]=open&filter[owner]=me, into a struct or
+// map[string]T field.
+func decodeDeepObject(field reflect.Value, typ reflect.Type, query url.Values, name string) error {
+	values := deepValues(query, name)
+	if len(values) == 0 {
+		return nil
+	}
+	return setDeepValue(field, typ, values)
+}
+
+// deepValues returns values of name[key] query parameters by key. For
+// nested objects remaining segments are kept in the key, so
+// filter[range][gt] is returned as range[gt] for name filter.
+func deepValues(query url.Values, name string) url.Values {
+	values := url.Values{}
+	prefix := name + "["
+	for key, v := range query {
+		if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		key = key[len(prefix):]
+		i := strings.IndexByte(key, ']')
+		values[key[:i]+key[i+1:]] = v
+	}
+	return values
+}
+
+// isDeepObject reports whether typ is decoded as nested deep object.
+func isDeepObject(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		return typ != reflect.TypeOf(time.Time{}) && !isSetter(typ)
+	case reflect.Map:
+		return true
+	case reflect.Slice:
+		// slices of scalars are decoded from comma separated value
+		return isDeepObject(typ.Elem())
+	}
+	return false
+}
+
+// setDeepValue sets values on the struct fields tagged with query or
+// on the keys of map[string]T.
+func setDeepValue(field reflect.Value, typ reflect.Type, values url.Values) error {
+	switch typ.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(typ.Elem())
+		if err := setDeepValue(elem.Elem(), typ.Elem(), values); err != nil {
+			return err
+		}
+		field.Set(elem)
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return fmt.Errorf("deepObject requires map with string key, got %v", typ)
+		}
+		m := reflect.MakeMap(typ)
+		for key, v := range values {
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := setValue(elem, typ.Elem(), v); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), elem)
+		}
+		field.Set(m)
+	case reflect.Slice:
+		return setDeepSlice(field, typ, values)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			tag := sf.Tag.Get("query")
+			if tag == "" {
+				continue
+			}
+			conf, err := queryFieldConf(sf.Type, tag)
+			if err != nil {
+				return err
+			}
+			if isDeepObject(sf.Type) {
+				if err := decodeDeepObject(field.Field(i), sf.Type, values, conf.name); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := decodeQuery(field.Field(i), sf.Type, values, conf); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("deepObject requires struct, map or slice field, got %v", typ)
+	}
+	return nil
+}
+
+// setDeepSlice sets slice elements from indexed values, for example
+// 0[sku]=a&0[qty]=2&1[sku]=b. Indices are sorted numerically, so sparse
+// indices result in consecutive elements.
+func setDeepSlice(field reflect.Value, typ reflect.Type, values url.Values) error {
+	indices := make(map[int]string)
+	for key := range values {
+		index := key
+		if i := strings.IndexByte(key, '['); i > 0 {
+			index = key[:i]
+		}
+		n, err := strconv.Atoi(index)
+		if err != nil || n < 0 {
+			return fmt.Errorf("deepObject slice requires numeric index, got %q", index)
+		}
+		indices[n] = index
+	}
+	order := make([]int, 0, len(indices))
+	for n := range indices {
+		order = append(order, n)
+	}
+	sort.Ints(order)
+
+	elemType := typ.Elem()
+	slice := reflect.MakeSlice(typ, len(order), len(order))
+	for i, n := range order {
+		index := indices[n]
+		var err error
+		if isDeepObject(elemType) {
+			err = setDeepValue(slice.Index(i), elemType, deepValues(values, index))
+		} else if len(values[index]) == 0 {
+			err = fmt.Errorf("deepObject slice element %d requires a value", n)
+		} else {
+			err = setValue(slice.Index(i), elemType, values[index])
+		}
+		if err != nil {
+			return fmt.Errorf("index %d: %w", n, err)
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+// setValue resolves values on the field, all of them for slice fields
+// otherwise the first one.
+func setValue(field reflect.Value, typ reflect.Type, values []string) error {
+	if typ.Kind() == reflect.Slice && !isTextUnmarshaler(typ) {
+		return resolveValues(field, typ, values)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return resolveValue(field, typ, values[0])
+}
+
 type URLParam func(key string) string
 
-func decodePath(field reflect.Value, typ reflect.Type, fn URLParam, tag string) error {
-	if path := fn(tag); path != "" {
+// WildcardParam is the name of the path value holding the wildcard
+// remainder of the route, for example /files/* in chi.
+const WildcardParam = "*"
+
+func decodePath(field reflect.Value, typ reflect.Type, fn URLParam, conf fieldConf) error {
+	path := fn(conf.name)
+	if conf.wildcard || conf.name == WildcardParam {
+		// routers differ in leading slash of the wildcard value
+		path = strings.TrimLeft(path, "/")
+	}
+	if path != "" {
 		if err := resolveValue(field, typ, path); err != nil {
 			return err
 		}
@@ -126,7 +646,11 @@ func decodePath(field reflect.Value, typ reflect.Type, fn URLParam, tag string)
 	return nil
 }
 
-func decodeHeader(field reflect.Value, typ reflect.Type, header http.Header, tag string) error {
+func decodeHeader(field reflect.Value, typ reflect.Type, header http.Header, conf fieldConf) error {
+	if conf.prefix {
+		return decodeHeaderPrefix(field, typ, header, conf.name)
+	}
+	tag := conf.name
 	if field.Kind() == reflect.Slice {
 		if err := resolveValues(field, typ, header.Values(tag)); err != nil {
 			return err
@@ -140,3 +664,61 @@ func decodeHeader(field reflect.Value, typ reflect.Type, header http.Header, tag
 	}
 	return nil
 }
+
+// decodeCookie decodes value of the cookie into field and reports
+// whether the cookie was present. Missing cookie leaves the field
+// unchanged, same as empty cookie with omitempty.
+func decodeCookie(field reflect.Value, typ reflect.Type, r *http.Request, conf fieldConf) (bool, error) {
+	cookie, err := r.Cookie(conf.name)
+	if errors.Is(err, http.ErrNoCookie) || (conf.omitempty && err == nil && cookie.Value == "") {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, resolveValue(field, typ, cookie.Value)
+}
+
+// metaValue returns request metadata bound by meta tag, method or
+// path.
+func metaValue(r *http.Request, name string) (string, error) {
+	switch name {
+	case "method":
+		return r.Method, nil
+	case "path":
+		return r.URL.Path, nil
+	}
+	return "", fmt.Errorf("unsupported meta tag: %s", name)
+}
+
+// decodeHeaderPrefix collects all headers starting with prefix into
+// map[string]string or map[string][]string field. Map keys are
+// canonical header names.
+func decodeHeaderPrefix(field reflect.Value, typ reflect.Type, header http.Header, prefix string) error {
+	if typ.Kind() != reflect.Map || typ.Key().Kind() != reflect.String {
+		return fmt.Errorf("header prefix %s requires map field, got %v", prefix, typ)
+	}
+	elem := typ.Elem()
+	if elem.Kind() != reflect.String &&
+		(elem.Kind() != reflect.Slice || elem.Elem().Kind() != reflect.String) {
+		return fmt.Errorf("header prefix %s requires map of string or []string, got %v", prefix, typ)
+	}
+
+	prefix = strings.ToLower(prefix)
+	m := reflect.MakeMap(typ)
+	for key, values := range header {
+		if len(values) == 0 || !strings.HasPrefix(strings.ToLower(key), prefix) {
+			continue
+		}
+		key = textproto.CanonicalMIMEHeaderKey(key)
+		if elem.Kind() == reflect.String {
+			m.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), reflect.ValueOf(values[0]).Convert(elem))
+			continue
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), reflect.ValueOf(append([]string(nil), values...)).Convert(elem))
+	}
+	if m.Len() > 0 {
+		field.Set(m)
+	}
+	return nil
+}
@@ -0,0 +1,720 @@
+package httputil
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pathParams(params map[string]string) RequestURLParam {
+	return func(r *http.Request, key string) string {
+		return params[key]
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type request struct {
+		ID     int64    `path:"id"`
+		Query  string   `query:"q"`
+		Tags   []string `query:"tag,explode"`
+		APIKey string   `header:"X-Api-Key"`
+	}
+
+	r, _ := http.NewRequest("GET", "/articles/5?q=test&tag=a&tag=b", nil)
+	r.Header.Set("X-Api-Key", "secret")
+
+	var req request
+	err := Decode(r, pathParams(map[string]string{"id": "5"}), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, request{ID: 5, Query: "test", Tags: []string{"a", "b"}, APIKey: "secret"}, req)
+}
+
+func TestDecoder_RejectUnknownQuery(t *testing.T) {
+	type Filter struct {
+		Status string `query:"filter"`
+	}
+	type request struct {
+		Filter
+		Query string   `query:"q"`
+		IDs   []string `query:"id,explode"`
+	}
+
+	decoder := NewDecoder(pathParams(nil), RejectUnknownQuery())
+
+	t.Run("known params", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/?q=test&id=1&id=2&filter[status]=open", nil)
+		var req request
+		assert.NoError(t, decoder.Decode(r, &req))
+		assert.Equal(t, []string{"1", "2"}, req.IDs)
+	})
+
+	t.Run("unknown param", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/?q=test&foo=bar", nil)
+		var req request
+		err := decoder.Decode(r, &req)
+		assert.True(t, errors.Is(err, ErrUnknownQueryParam))
+		assert.EqualError(t, err, "unknown query parameter: foo")
+	})
+
+	t.Run("lenient by default", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/?q=test&foo=bar", nil)
+		var req request
+		assert.NoError(t, Decode(r, pathParams(nil), &req))
+		assert.Equal(t, "test", req.Query)
+	})
+}
+
+func TestParamsOf(t *testing.T) {
+	type request struct {
+		ID      int64    `path:"id"`
+		Query   string   `query:"q"`
+		Tags    []string `query:"tag,explode"`
+		APIKey  string   `header:"X-Api-Key"`
+		Ignored string
+	}
+
+	params := ParamsOf(&request{})
+	assert.Equal(t, []Param{
+		{Name: "id", In: InPath, Type: reflect.TypeOf(int64(0)), Required: true, Style: "simple"},
+		{Name: "q", In: InQuery, Type: reflect.TypeOf(""), Style: "form"},
+		{Name: "tag", In: InQuery, Type: reflect.TypeOf([]string{}), Style: "form", Explode: true},
+		{Name: "X-Api-Key", In: InHeader, Type: reflect.TypeOf(""), Style: "simple"},
+	}, params)
+}
+
+func TestDecode_HeaderPrefix(t *testing.T) {
+	type request struct {
+		Forwarded    map[string]string   `header:"X-Forwarded-,prefix"`
+		ForwardedAll map[string][]string `header:"x-forwarded-,prefix"`
+		Missing      map[string]string   `header:"X-Missing-,prefix"`
+	}
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Add("X-Forwarded-For", "10.0.0.1")
+	r.Header.Add("X-Forwarded-For", "10.0.0.2")
+	r.Header.Add("x-forwarded-proto", "https")
+	r.Header.Add("X-Request-Id", "abc")
+
+	var req request
+	assert.NoError(t, Decode(r, pathParams(nil), &req))
+	assert.Equal(t, map[string]string{
+		"X-Forwarded-For":   "10.0.0.1",
+		"X-Forwarded-Proto": "https",
+	}, req.Forwarded)
+	assert.Equal(t, map[string][]string{
+		"X-Forwarded-For":   {"10.0.0.1", "10.0.0.2"},
+		"X-Forwarded-Proto": {"https"},
+	}, req.ForwardedAll)
+	assert.Nil(t, req.Missing)
+
+	invalid := struct {
+		Forwarded string `header:"X-Forwarded-,prefix"`
+	}{}
+	assert.Error(t, Decode(r, pathParams(nil), &invalid))
+}
+
+func TestDecoder_SnakeCaseQuery(t *testing.T) {
+	type request struct {
+		ClientID    string
+		PageSize    int
+		HTTPTimeout *int
+		RedirectURI string `query:"redirect"`
+		ExtraTags   []string
+	}
+
+	r, _ := http.NewRequest("GET", "/?client_id=abc&page_size=10&http_timeout=5&redirect=/home&redirect_uri=/ignored&extra_tags=a,b", nil)
+
+	var req request
+	assert.NoError(t, NewDecoder(pathParams(nil), SnakeCaseQuery()).Decode(r, &req))
+	assert.Equal(t, "abc", req.ClientID)
+	assert.Equal(t, 10, req.PageSize)
+	assert.Equal(t, 5, *req.HTTPTimeout)
+	assert.Equal(t, "/home", req.RedirectURI)
+	assert.Equal(t, []string{"a", "b"}, req.ExtraTags)
+
+	var plain request
+	assert.NoError(t, Decode(r, pathParams(nil), &plain))
+	assert.Empty(t, plain.ClientID)
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ClientID":   "client_id",
+		"HTTPServer": "http_server",
+		"Name":       "name",
+		"PerPage":    "per_page",
+		"Page2Size":  "page2_size",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, snakeCase(name), name)
+	}
+}
+
+type articleStatus string
+
+func (articleStatus) Enum() []any {
+	return []any{"draft", "published"}
+}
+
+type priority int
+
+func (*priority) Enum() []any {
+	return []any{1, 2, 3}
+}
+
+func TestDecode_Enum(t *testing.T) {
+	type request struct {
+		Status   articleStatus   `query:"status"`
+		Statuses []articleStatus `query:"statuses"`
+		Priority *priority       `query:"priority"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?status=draft&statuses=draft,published&priority=2", nil)
+	var req request
+	assert.NoError(t, Decode(r, pathParams(nil), &req))
+	assert.Equal(t, articleStatus("draft"), req.Status)
+	assert.Equal(t, []articleStatus{"draft", "published"}, req.Statuses)
+	assert.Equal(t, priority(2), *req.Priority)
+
+	for _, query := range []string{"status=archived", "statuses=draft,archived", "priority=5"} {
+		r, _ = http.NewRequest("GET", "/?"+query, nil)
+		err := Decode(r, pathParams(nil), &request{})
+		assert.ErrorContains(t, err, "allowed values", query)
+	}
+}
+
+func TestDecode_WildcardPath(t *testing.T) {
+	type request struct {
+		Bucket string `path:"bucket"`
+		Key    string `path:"*"`
+	}
+	type stdRequest struct {
+		Key string `path:"key,wildcard"`
+	}
+
+	r, _ := http.NewRequest("GET", "/files/docs/reports/2023.pdf", nil)
+
+	var req request
+	assert.NoError(t, Decode(r, pathParams(map[string]string{
+		"bucket": "docs",
+		"*":      "reports/2023.pdf",
+	}), &req))
+	assert.Equal(t, request{Bucket: "docs", Key: "reports/2023.pdf"}, req)
+
+	var std stdRequest
+	assert.NoError(t, Decode(r, pathParams(map[string]string{
+		"key": "/reports/2023.pdf",
+	}), &std))
+	assert.Equal(t, "reports/2023.pdf", std.Key)
+}
+
+func TestDecode_DeepObject(t *testing.T) {
+	type filter struct {
+		Status string `query:"status"`
+		Limit  int    `query:"limit"`
+	}
+	type request struct {
+		Filter filter            `query:"filter,deepObject"`
+		Labels map[string]string `query:"label,deepObject"`
+		Counts map[string]int    `query:"count,deepObject"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?filter[status]=open&filter[limit]=10&label[owner]=me&label[team]=core&count[a]=1", nil)
+
+	var req request
+	assert.NoError(t, NewDecoder(nil, RejectUnknownQuery()).Decode(r, &req))
+	assert.Equal(t, filter{Status: "open", Limit: 10}, req.Filter)
+	assert.Equal(t, map[string]string{"owner": "me", "team": "core"}, req.Labels)
+	assert.Equal(t, map[string]int{"a": 1}, req.Counts)
+
+	r, _ = http.NewRequest("GET", "/?count[a]=x", nil)
+	assert.Error(t, Decode(r, nil, &request{}))
+
+	type invalid struct {
+		Filter map[int]string `query:"filter,deepObject"`
+	}
+	r, _ = http.NewRequest("GET", "/?filter[1]=a", nil)
+	assert.ErrorContains(t, Decode(r, nil, &invalid{}), "string key")
+}
+
+func TestDecode_RequiredDeepObject(t *testing.T) {
+	type filter struct {
+		Status string `query:"status"`
+	}
+	type request struct {
+		Filter filter            `query:"filter,deepObject,required"`
+		Labels map[string]string `query:"label,deepObject,required"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?filter[status]=open&label[owner]=me", nil)
+	var req request
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Equal(t, "open", req.Filter.Status)
+	assert.Equal(t, map[string]string{"owner": "me"}, req.Labels)
+
+	r, _ = http.NewRequest("GET", "/?filter=open&label[owner]=me", nil)
+	err := Decode(r, nil, &request{})
+	assert.ErrorIs(t, err, ErrRequiredParam)
+	assert.EqualError(t, err, "query filter: required parameter is missing")
+}
+
+func TestDecode_DecodeError(t *testing.T) {
+	type request struct {
+		ID    int64  `path:"id"`
+		Page  int    `query:"page"`
+		Limit int    `query:"limit"`
+		Sort  string `query:"sort"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?page=x&limit=y&sort=name", nil)
+	var req request
+	err := Decode(r, pathParams(map[string]string{"id": "7"}), &req)
+
+	var derr *DecodeError
+	assert.True(t, errors.As(err, &derr))
+	assert.Len(t, derr.Fields, 2)
+	assert.Equal(t, FieldError{Tag: InQuery, Name: "page", Err: derr.Fields[0].Err}, derr.Fields[0])
+	assert.Equal(t, "limit", derr.Fields[1].Name)
+	assert.Equal(t, "name", req.Sort)
+	assert.Equal(t, int64(7), req.ID)
+
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr))
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Contains(t, err.Error(), "query page: ")
+}
+
+func TestDecode_RequiredQuery(t *testing.T) {
+	type request struct {
+		ID     int64   `query:"id,required"`
+		Filter *string `query:"filter,required"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?id=5&filter=", nil)
+	var req request
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Equal(t, int64(5), req.ID)
+	assert.Equal(t, "", *req.Filter)
+
+	r, _ = http.NewRequest("GET", "/?filter=a", nil)
+	err := Decode(r, nil, &request{})
+	assert.ErrorIs(t, err, ErrRequiredParam)
+	assert.EqualError(t, err, "query id: required parameter is missing")
+
+	type invalid struct {
+		ID int64 `query:"id,required,omitempty"`
+	}
+	r, _ = http.NewRequest("GET", "/?id=5", nil)
+	assert.ErrorContains(t, Decode(r, nil, &invalid{}), "mutually exclusive")
+}
+
+func TestDecode_NestedDeepObject(t *testing.T) {
+	type rangeFilter struct {
+		Gt *int `query:"gt"`
+		Lt *int `query:"lt"`
+	}
+	type created struct {
+		Range rangeFilter `query:"range"`
+	}
+	type filter struct {
+		Status  string       `query:"status"`
+		Price   *rangeFilter `query:"price"`
+		Created *created     `query:"created"`
+	}
+	type request struct {
+		Filter filter `query:"filter,deepObject"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?filter[status]=open&filter[price][gt]=5&filter[price][lt]=10&filter[created][range][gt]=2020", nil)
+
+	var req request
+	assert.NoError(t, NewDecoder(nil, RejectUnknownQuery()).Decode(r, &req))
+	assert.Equal(t, "open", req.Filter.Status)
+	assert.Equal(t, 5, *req.Filter.Price.Gt)
+	assert.Equal(t, 10, *req.Filter.Price.Lt)
+	assert.Equal(t, 2020, *req.Filter.Created.Range.Gt)
+	assert.Nil(t, req.Filter.Created.Range.Lt)
+
+	r, _ = http.NewRequest("GET", "/?filter[status]=open", nil)
+	req = request{}
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Nil(t, req.Filter.Price)
+
+	r, _ = http.NewRequest("GET", "/?filter[price][gt]=x", nil)
+	assert.Error(t, Decode(r, nil, &request{}))
+}
+
+func TestDecode_Base64Query(t *testing.T) {
+	type request struct {
+		Cursor []byte `query:"cursor,base64"`
+		Token  []byte `query:"token,base64"`
+	}
+
+	// "\xfb\xff\xbf" encodes with URL specific characters: -_-_
+	r, _ := http.NewRequest("GET", "/?cursor=-_-_&token=aGVsbG8%3D", nil)
+	var req request
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Equal(t, []byte{0xfb, 0xff, 0xbf}, req.Cursor)
+	assert.Equal(t, []byte("hello"), req.Token)
+
+	r, _ = http.NewRequest("GET", "/?cursor=%2B_%2F", nil)
+	assert.ErrorContains(t, Decode(r, nil, &request{}), "invalid base64")
+
+	type invalid struct {
+		Cursor string `query:"cursor,base64"`
+	}
+	r, _ = http.NewRequest("GET", "/?cursor=aGk", nil)
+	assert.ErrorContains(t, Decode(r, nil, &invalid{}), "requires []byte")
+}
+
+func TestDecoder_DecodeWithMeta(t *testing.T) {
+	type Paging struct {
+		Page int `query:"page"`
+		Size int `query:"size"`
+	}
+	type body struct {
+		Title string `json:"title"`
+	}
+	type request struct {
+		Paging
+		ID      int64   `path:"id"`
+		Visible bool    `query:"visible"`
+		Name    *string `query:"name"`
+		Tenant  string  `header:"X-Tenant"`
+		Body    body    `body:"article"`
+	}
+
+	decoder := NewDecoder(pathParams(map[string]string{"id": "7"}))
+
+	r, _ := http.NewRequest("PATCH", "/articles/7?visible=false&page=2", strings.NewReader(`{"title":""}`))
+	var req request
+	mask, err := decoder.DecodeWithMeta(r, &req)
+	assert.NoError(t, err)
+	assert.Equal(t, FieldMask{
+		"ID":      {},
+		"Visible": {},
+		"Page":    {},
+		"Body":    {},
+	}, mask)
+	assert.True(t, mask.Has("Visible"))
+	assert.False(t, mask.Has("Name"))
+
+	r, _ = http.NewRequest("PATCH", "/articles/7?name=x", nil)
+	r.Header.Set("X-Tenant", "acme")
+	mask, err = decoder.DecodeWithMeta(r, &request{})
+	assert.NoError(t, err)
+	assert.Equal(t, FieldMask{"ID": {}, "Name": {}, "Tenant": {}}, mask)
+}
+
+// optionalValue mimics optional.Type[T].
+type optionalValue[T any] struct {
+	value T
+	set   bool
+}
+
+func (o *optionalValue[T]) Set(v T) {
+	o.value, o.set = v, true
+}
+
+func (o optionalValue[T]) IsZero() bool {
+	return !o.set
+}
+
+// nullableValue mimics nullable.Nullable[T].
+type nullableValue[T any] struct {
+	optionalValue[T]
+	null bool
+}
+
+func (n *nullableValue[T]) SetNull() {
+	var zero T
+	n.value, n.set, n.null = zero, true, true
+}
+
+func TestDecode_OptionalNullable(t *testing.T) {
+	type request struct {
+		Name     optionalValue[string] `query:"name"`
+		Limit    optionalValue[int]    `query:"limit"`
+		Active   optionalValue[bool]   `query:"active"`
+		Owner    nullableValue[string] `query:"owner"`
+		Priority nullableValue[int]    `query:"priority"`
+		Archived nullableValue[bool]   `query:"archived"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?name=test&limit=10&active=true&owner=&priority=3", nil)
+	var req request
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Equal(t, optionalValue[string]{value: "test", set: true}, req.Name)
+	assert.Equal(t, optionalValue[int]{value: 10, set: true}, req.Limit)
+	assert.Equal(t, optionalValue[bool]{value: true, set: true}, req.Active)
+	assert.True(t, req.Owner.null)
+	assert.Equal(t, 3, req.Priority.value)
+	assert.False(t, req.Priority.null)
+	assert.True(t, req.Archived.IsZero())
+
+	r, _ = http.NewRequest("GET", "/?limit=x", nil)
+	assert.Error(t, Decode(r, nil, &request{}))
+}
+
+func TestDecoder_DecodeWithDefaults(t *testing.T) {
+	type Paging struct {
+		Page int `query:"page"`
+		Size int `query:"size"`
+	}
+	type request struct {
+		Paging
+		Sort   string                `query:"sort"`
+		Draft  *bool                 `query:"draft"`
+		Owner  optionalValue[string] `query:"owner"`
+		Tenant string                `header:"X-Tenant"`
+	}
+
+	draft := true
+	defaults := request{
+		Paging: Paging{Page: 1, Size: 20},
+		Sort:   "-created",
+		Draft:  &draft,
+		Owner:  optionalValue[string]{value: "me", set: true},
+		Tenant: "default",
+	}
+
+	r, _ := http.NewRequest("GET", "/?size=50&draft=false&sort=", nil)
+	var req request
+	assert.NoError(t, NewDecoder(nil).DecodeWithDefaults(r, &req, &defaults))
+	assert.Equal(t, 1, req.Page)
+	assert.Equal(t, 50, req.Size)
+	assert.Equal(t, "", req.Sort)
+	assert.False(t, *req.Draft)
+	assert.Equal(t, "me", req.Owner.value)
+	assert.Equal(t, "default", req.Tenant)
+	assert.True(t, *defaults.Draft)
+
+	assert.Error(t, NewDecoder(nil).DecodeWithDefaults(r, &req, &Paging{}))
+}
+
+func TestDecoder_DecodeWithDefaultsBody(t *testing.T) {
+	type payload struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	type request struct {
+		Limit int     `query:"limit"`
+		Body  payload `body:"json"`
+	}
+	defaults := request{Limit: 10, Body: payload{Name: "def", Count: 1}}
+
+	r, _ := http.NewRequest("POST", "/", strings.NewReader(`{"name":"req","count":7}`))
+	r.Header.Set("Content-Type", "application/json")
+	var req request
+	assert.NoError(t, NewDecoder(nil).DecodeWithDefaults(r, &req, &defaults))
+	assert.Equal(t, request{Limit: 10, Body: payload{Name: "req", Count: 7}}, req)
+
+	r, _ = http.NewRequest("POST", "/?limit=5", http.NoBody)
+	req = request{}
+	assert.NoError(t, NewDecoder(nil).DecodeWithDefaults(r, &req, &defaults))
+	assert.Equal(t, request{Limit: 5, Body: payload{Name: "def", Count: 1}}, req)
+}
+
+func TestDecode_DefaultQuery(t *testing.T) {
+	type request struct {
+		Limit  int           `query:"limit,default=50"`
+		Sort   string        `query:"sort,default=-created"`
+		Draft  *bool         `query:"draft,default=true"`
+		Status articleStatus `query:"status,default=draft"`
+	}
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	var req request
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Equal(t, 50, req.Limit)
+	assert.Equal(t, "-created", req.Sort)
+	assert.True(t, *req.Draft)
+	assert.Equal(t, articleStatus("draft"), req.Status)
+
+	r, _ = http.NewRequest("GET", "/?limit=10&draft=false&sort=", nil)
+	req = request{}
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Equal(t, 10, req.Limit)
+	assert.Equal(t, "", req.Sort)
+	assert.False(t, *req.Draft)
+
+	// defaults are decoded once, fields must not share them
+	r, _ = http.NewRequest("GET", "/", nil)
+	first, second := request{}, request{}
+	assert.NoError(t, Decode(r, nil, &first))
+	*first.Draft = false
+	assert.NoError(t, Decode(r, nil, &second))
+	assert.True(t, *second.Draft)
+
+	type invalid struct {
+		Limit int `query:"limit,default=many"`
+	}
+	r, _ = http.NewRequest("GET", "/?limit=10", nil)
+	err := Decode(r, nil, &invalid{})
+	assert.ErrorContains(t, err, `query limit: invalid default "many"`)
+	var decodeErr *DecodeError
+	assert.False(t, errors.As(err, &decodeErr), "invalid tag is not a request error")
+}
+
+func TestDecode_Cookie(t *testing.T) {
+	type request struct {
+		Session string `cookie:"session_id"`
+		Theme   string `cookie:""`
+		Limit   *int   `cookie:"limit,omitempty"`
+		Missing int    `cookie:"missing"`
+	}
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: "abc"})
+	r.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	r.AddCookie(&http.Cookie{Name: "limit", Value: ""})
+
+	var req request
+	mask, err := NewDecoder(nil).DecodeWithMeta(r, &req)
+	assert.NoError(t, err)
+	assert.Equal(t, request{Session: "abc", Theme: "dark"}, req)
+	assert.Equal(t, FieldMask{"Session": {}, "Theme": {}}, mask)
+
+	r, _ = http.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "missing", Value: "x"})
+	assert.ErrorContains(t, Decode(r, nil, &request{}), "cookie missing: ")
+
+	assert.Contains(t, ParamsOf(request{}), Param{Name: "theme", In: InCookie, Type: reflect.TypeOf(""), Style: "form"})
+}
+
+func TestDecode_Meta(t *testing.T) {
+	type request struct {
+		Method string `meta:"method"`
+		Path   string `meta:"path"`
+		ID     string `path:"id"`
+	}
+
+	r, _ := http.NewRequest("GET", "/articles/42?fields=title", nil)
+	var req request
+	assert.NoError(t, NewDecoder(pathParams(map[string]string{"id": "42"}), SnakeCaseQuery()).Decode(r, &req))
+	assert.Equal(t, request{Method: "GET", Path: "/articles/42", ID: "42"}, req)
+
+	type invalid struct {
+		Host string `meta:"host"`
+	}
+	assert.ErrorContains(t, Decode(r, nil, &invalid{}), "unsupported meta tag: host")
+}
+
+func TestDecoder_CaseInsensitiveQuery(t *testing.T) {
+	type request struct {
+		Field  []string          `query:"field,explode"`
+		Status string            `query:"status"`
+		Filter map[string]string `query:"filter,deepObject"`
+	}
+
+	decoder := NewDecoder(nil, CaseInsensitiveQuery(), RejectUnknownQuery())
+
+	r, _ := http.NewRequest("GET", "/?Field=a&field=b&STATUS=open&Filter[owner]=me", nil)
+	var req request
+	assert.NoError(t, decoder.Decode(r, &req))
+	assert.Equal(t, []string{"a", "b"}, req.Field)
+	assert.Equal(t, "open", req.Status)
+	assert.Equal(t, map[string]string{"owner": "me"}, req.Filter)
+
+	r, _ = http.NewRequest("GET", "/?Field=a&field=b", nil)
+	req = request{}
+	assert.ErrorIs(t, NewDecoder(nil, RejectUnknownQuery()).Decode(r, &req), ErrUnknownQueryParam)
+}
+
+func TestDecode_DeepObjectSlice(t *testing.T) {
+	type item struct {
+		SKU string `query:"sku"`
+		Qty int    `query:"qty"`
+	}
+	type order struct {
+		ID    int    `query:"id"`
+		Items []item `query:"items"`
+	}
+	type request struct {
+		Items []item `query:"items,deepObject"`
+		Order order  `query:"order,deepObject"`
+		IDs   []int  `query:"ids,deepObject"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?items[2][sku]=c&items[0][sku]=a&items[0][qty]=2&items[10][sku]=d"+
+		"&order[id]=5&order[items][0][sku]=x&order[items][1][qty]=3&ids[1]=20&ids[0]=10", nil)
+
+	var req request
+	assert.NoError(t, Decode(r, nil, &req))
+	assert.Equal(t, []item{{SKU: "a", Qty: 2}, {SKU: "c"}, {SKU: "d"}}, req.Items)
+	assert.Equal(t, order{ID: 5, Items: []item{{SKU: "x"}, {Qty: 3}}}, req.Order)
+	assert.Equal(t, []int{10, 20}, req.IDs)
+
+	r, _ = http.NewRequest("GET", "/?items[first][sku]=a", nil)
+	assert.ErrorContains(t, Decode(r, nil, &request{}), "numeric index")
+
+	r, _ = http.NewRequest("GET", "/?items[0][qty]=x", nil)
+	assert.ErrorContains(t, Decode(r, nil, &request{}), "index 0")
+
+	var scalars struct {
+		Items []string `query:"items,deepObject"`
+	}
+	r, _ = http.NewRequest("GET", "/?items[0][x]=a", nil)
+	assert.ErrorContains(t, Decode(r, nil, &scalars), "deepObject slice element 0 requires a value")
+}
+
+func TestDecoder_FlagQuery(t *testing.T) {
+	type request struct {
+		Verbose bool   `query:"verbose"`
+		Debug   *bool  `query:"debug"`
+		Name    string `query:"name"`
+	}
+
+	decode := func(d *Decoder, query string) (request, error) {
+		r, _ := http.NewRequest("GET", "/?"+query, nil)
+		var req request
+		err := d.Decode(r, &req)
+		return req, err
+	}
+
+	flags := NewDecoder(nil, FlagQuery())
+	req, err := decode(flags, "verbose&debug&name")
+	assert.NoError(t, err)
+	assert.True(t, req.Verbose)
+	assert.True(t, *req.Debug)
+	assert.Empty(t, req.Name)
+
+	req, err = decode(flags, "verbose=false&debug=false")
+	assert.NoError(t, err)
+	assert.False(t, req.Verbose)
+	assert.False(t, *req.Debug)
+
+	req, err = decode(flags, "")
+	assert.NoError(t, err)
+	assert.False(t, req.Verbose)
+	assert.Nil(t, req.Debug)
+
+	_, err = decode(NewDecoder(nil), "verbose")
+	assert.ErrorContains(t, err, "query verbose: ")
+}
+
+func TestDecoder_With(t *testing.T) {
+	type request struct {
+		Status string `query:"status"`
+	}
+	base := NewDecoder(pathParams(nil), WithBodyDecoder("text", func(r io.Reader, v any) error { return nil }, "text/plain"))
+	strict := base.With(RejectUnknownQuery(), WithBodyDecoder("csv", func(r io.Reader, v any) error { return nil }, "text/csv"))
+
+	r, _ := http.NewRequest("GET", "/?status=open&page=2", nil)
+	var req request
+	assert.NoError(t, base.Decode(r, &req))
+	assert.Equal(t, "open", req.Status)
+	assert.ErrorIs(t, strict.Decode(r, &request{}), ErrUnknownQueryParam)
+
+	assert.False(t, base.rejectUnknownQuery)
+	assert.NotContains(t, base.bodyDecoders, "csv")
+	assert.NotContains(t, base.contentTypes, "text/csv")
+	assert.Contains(t, strict.bodyDecoders, "text")
+	assert.Equal(t, "text", strict.contentTypes["text/plain"])
+}
@@ -0,0 +1,201 @@
+package httputil
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encode returns query values of fields tagged with query, encoded the
+// way Decode reads them, so structs used for decoding requests can be
+// used for building outbound ones:
+//
+//	query, err := httputil.Encode(ListArticles{Status: "open", Tags: []string{"go", "http"}})
+//	// status=open&tag=go,http
+//
+// Slices are comma separated unless tagged with explode, deepObject
+// fields are encoded as name[key] parameters and base64 fields with URL
+// base64 encoding. Nil pointers, slices and maps are omitted, zero
+// values are omitted for fields tagged with omitempty.
+func Encode(i any) (url.Values, error) {
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("invalid encode value: nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid encode type: %v", v.Kind())
+	}
+
+	query := url.Values{}
+	if err := encodeStruct(query, v); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+func encodeStruct(query url.Values, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(InQuery)
+		conf := parseFieldConf(tag)
+		if isNested(field, conf) {
+			if err := encodeStruct(query, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+		if err := encodeField(query, v.Field(i), conf); err != nil {
+			return fmt.Errorf("query %s: %w", conf.name, err)
+		}
+	}
+	return nil
+}
+
+func encodeField(query url.Values, v reflect.Value, conf fieldConf) error {
+	if isEmptyValue(v) || (conf.omitempty && v.IsZero()) {
+		return nil
+	}
+	if conf.deepObject {
+		return encodeDeep(query, conf.name, v)
+	}
+	if conf.base64 {
+		query.Set(conf.name, base64.RawURLEncoding.EncodeToString(v.Bytes()))
+		return nil
+	}
+	if v.Kind() == reflect.Slice && !isText(v) {
+		values := make([]string, v.Len())
+		for i := range values {
+			s, err := formatValue(v.Index(i))
+			if err != nil {
+				return err
+			}
+			values[i] = s
+		}
+		if conf.explode {
+			query[conf.name] = values
+		} else {
+			query.Set(conf.name, strings.Join(values, ","))
+		}
+		return nil
+	}
+	s, err := formatValue(v)
+	if err != nil {
+		return err
+	}
+	query.Set(conf.name, s)
+	return nil
+}
+
+// encodeDeep encodes v as name[key] parameters, nested objects add
+// more bracket segments.
+func encodeDeep(query url.Values, name string, v reflect.Value) error {
+	if isEmptyValue(v) {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if !isDeepObject(v.Type()) {
+		s, err := formatValue(v)
+		if err != nil {
+			return err
+		}
+		query.Set(name, s)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			if err := encodeDeep(query, name+"["+key.String()+"]", v.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeDeep(query, name+"["+strconv.Itoa(i)+"]", v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag := t.Field(i).Tag.Get(InQuery)
+			if tag == "" {
+				continue
+			}
+			conf := parseFieldConf(tag)
+			if conf.omitempty && v.Field(i).IsZero() {
+				continue
+			}
+			if err := encodeDeep(query, name+"["+conf.name+"]", v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isEmptyValue reports whether v is nil pointer, slice or map.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// isText reports whether v is encoded by encoding.TextMarshaler.
+func isText(v reflect.Value) bool {
+	_, ok := v.Interface().(encoding.TextMarshaler)
+	return ok
+}
+
+// formatValue formats v same as resolve parses it.
+func formatValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	switch t := v.Interface().(type) {
+	case time.Time:
+		return t.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return t.String(), nil
+	case encoding.TextMarshaler:
+		b, err := t.MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type: %v", v.Type())
+}
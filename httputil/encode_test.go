@@ -0,0 +1,77 @@
+package httputil
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	type rangeFilter struct {
+		Gt *int `query:"gt"`
+		Lt *int `query:"lt,omitempty"`
+	}
+	type item struct {
+		SKU string `query:"sku"`
+		Qty int    `query:"qty"`
+	}
+	type Paging struct {
+		Page int `query:"page"`
+	}
+	type request struct {
+		Paging
+		Status   articleStatus     `query:"status"`
+		Tags     []string          `query:"tag,explode"`
+		IDs      []int64           `query:"ids"`
+		Draft    *bool             `query:"draft"`
+		Since    time.Time         `query:"since"`
+		Timeout  time.Duration     `query:"timeout"`
+		Period   Period            `query:"period"`
+		Ratio    float64           `query:"ratio"`
+		IP       net.IP            `query:"ip"`
+		Cursor   []byte            `query:"cursor,base64"`
+		Price    rangeFilter       `query:"price,deepObject"`
+		Labels   map[string]string `query:"label,deepObject"`
+		Items    []item            `query:"items,deepObject"`
+		Note     string            `query:"note,omitempty"`
+		Internal string
+	}
+
+	gt, draft := 5, false
+	in := request{
+		Paging:  Paging{Page: 2},
+		Status:  "published",
+		Tags:    []string{"go", "http"},
+		IDs:     []int64{1, 2},
+		Draft:   &draft,
+		Since:   time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+		Timeout: 90 * time.Second,
+		Period:  Period(36 * time.Hour),
+		Ratio:   0.25,
+		IP:      net.ParseIP("10.0.0.1"),
+		Cursor:  []byte{0xfb, 0xff},
+		Price:   rangeFilter{Gt: &gt},
+		Labels:  map[string]string{"owner": "me"},
+		Items:   []item{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}},
+	}
+
+	query, err := Encode(&in)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "http"}, query["tag"])
+	assert.Equal(t, "1,2", query.Get("ids"))
+	assert.Equal(t, "5", query.Get("price[gt]"))
+	assert.False(t, query.Has("price[lt]"))
+	assert.Equal(t, "b", query.Get("items[1][sku]"))
+	assert.False(t, query.Has("note"))
+
+	r, _ := http.NewRequest("GET", "/?"+query.Encode(), nil)
+	var out request
+	assert.NoError(t, Decode(r, nil, &out))
+	assert.Equal(t, in, out)
+
+	_, err = Encode(42)
+	assert.Error(t, err)
+}
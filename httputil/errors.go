@@ -0,0 +1,50 @@
+package httputil
+
+import "strings"
+
+// FieldError describes failure of decoding a single path, query,
+// header or cookie field.
+type FieldError struct {
+	// Tag is the parameter location, one of InPath, InQuery, InHeader
+	// or InCookie.
+	Tag string
+	// Name is the parameter name.
+	Name string
+	// Err is the cause.
+	Err error
+}
+
+// Error interface method.
+func (e FieldError) Error() string {
+	return e.Tag + " " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned by Decode when one or more fields could not
+// be decoded. All fields are decoded before it is returned, so it lists
+// every failure.
+type DecodeError struct {
+	Fields []FieldError
+}
+
+// Error interface method.
+func (e *DecodeError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns causes of all failed fields.
+func (e *DecodeError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f.Err
+	}
+	return errs
+}
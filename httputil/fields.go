@@ -0,0 +1,290 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Parameter locations.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InCookie = "cookie"
+)
+
+// Param describes a request parameter bound by Decode.
+type Param struct {
+	Name     string
+	In       string
+	Type     reflect.Type
+	Required bool
+	Style    string
+	Explode  bool
+}
+
+// FieldMask is a set of paths of fields which had a value in the
+// request, see Decoder.DecodeWithMeta. Path is the Go field name, names
+// of nested struct fields are joined with a dot, for example
+// Filter.Status. Fields of embedded structs are not prefixed.
+type FieldMask map[string]struct{}
+
+// Has reports whether field at path had a value in the request.
+func (m FieldMask) Has(path string) bool {
+	_, ok := m[path]
+	return ok
+}
+
+// fieldConf holds the options parsed from a field tag, for example
+// `query:"id,explode"`, `path:"*"` or `body:"items,xml"`.
+type fieldConf struct {
+	name     string
+	explode  bool
+	format   string
+	prefix   bool
+	wildcard bool
+	required bool
+	// omitempty marks parameter as optional, it cannot be combined
+	// with required.
+	omitempty bool
+	// defaultValue is used when query parameter is missing.
+	defaultValue string
+	hasDefault   bool
+	// defaultField is defaultValue decoded into the field type, set by
+	// queryFieldConf.
+	defaultField reflect.Value
+	// base64 decodes std or URL base64 query value into []byte.
+	base64 bool
+	// stream decodes json array body lazily into an iterator field.
+	stream bool
+	// deepObject binds name[key] query parameters into struct or map.
+	deepObject bool
+	// flag sets bool field to true when query parameter is present
+	// without value, set by FlagQuery decoder option.
+	flag bool
+}
+
+// parseFieldConf parses tag value into fieldConf.
+func parseFieldConf(tag string) fieldConf {
+	parts := strings.Split(tag, ",")
+	conf := fieldConf{
+		name: parts[0],
+	}
+	for _, p := range parts[1:] {
+		if value, ok := strings.CutPrefix(p, "default="); ok {
+			conf.defaultValue, conf.hasDefault = value, true
+			continue
+		}
+		switch p {
+		case "explode":
+			conf.explode = true
+		case "prefix":
+			conf.prefix = true
+		case "wildcard":
+			conf.wildcard = true
+		case "deepObject":
+			conf.deepObject = true
+		case "required":
+			conf.required = true
+		case "omitempty":
+			conf.omitempty = true
+		case "stream":
+			conf.stream = true
+		case "base64":
+			conf.base64 = true
+		default:
+			// body format, json, xml or registered with WithBodyDecoder
+			conf.format = p
+		}
+	}
+	return conf
+}
+
+// queryConfKey identifies a query tag of a field type in queryConfs.
+type queryConfKey struct {
+	typ reflect.Type
+	tag string
+}
+
+// queryConfEntry is a cached result of queryFieldConf.
+type queryConfEntry struct {
+	conf fieldConf
+	err  error
+}
+
+// queryConfs caches query field configs, so tags are parsed and
+// defaults are decoded only once per field type.
+var queryConfs sync.Map // map[queryConfKey]queryConfEntry
+
+// queryFieldConf parses query tag of a field of type typ. Conflicting
+// options and default value which cannot be decoded into typ are
+// reported here, regardless of the request.
+func queryFieldConf(typ reflect.Type, tag string) (fieldConf, error) {
+	key := queryConfKey{typ: typ, tag: tag}
+	if e, ok := queryConfs.Load(key); ok {
+		entry := e.(queryConfEntry)
+		return entry.conf, entry.err
+	}
+	conf := parseFieldConf(tag)
+	var err error
+	switch {
+	case conf.required && conf.omitempty:
+		err = errors.New("required and omitempty are mutually exclusive")
+	case conf.hasDefault:
+		conf.defaultField = reflect.New(typ).Elem()
+		if err = setValue(conf.defaultField, typ, []string{conf.defaultValue}); err != nil {
+			err = fmt.Errorf("invalid default %q: %w", conf.defaultValue, err)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("query %s: %w", conf.name, err)
+	}
+	queryConfs.Store(key, queryConfEntry{conf: conf, err: err})
+	return conf, err
+}
+
+// copyValue returns a copy of v which does not share pointers or slice
+// elements with v, so cached defaults are not modified through fields.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(copyValue(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		return c
+	}
+	return v
+}
+
+// ParamsOf returns descriptors of all path, query, header and cookie
+// parameters which Decode binds into i. Descriptors follow the decoding
+// behavior, so they can be used to generate OpenAPI parameters.
+func ParamsOf(i any) []Param {
+	typ := reflect.TypeOf(i)
+	if typ == nil {
+		return nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return paramsOf(typ)
+}
+
+func paramsOf(t reflect.Type) []Param {
+	var params []Param
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Type.Kind() == reflect.Struct && !parseFieldConf(field.Tag.Get(InQuery)).deepObject {
+			params = append(params, paramsOf(field.Type)...)
+		}
+
+		if tag := field.Tag.Get(InPath); tag != "" {
+			params = append(params, Param{
+				Name:     parseFieldConf(tag).name,
+				In:       InPath,
+				Type:     field.Type,
+				Required: true,
+				Style:    "simple",
+			})
+		}
+
+		if tag := field.Tag.Get(InQuery); tag != "" {
+			conf := parseFieldConf(tag)
+			style := "form"
+			if conf.deepObject {
+				style = "deepObject"
+			}
+			params = append(params, Param{
+				Name:     conf.name,
+				In:       InQuery,
+				Type:     field.Type,
+				Required: conf.required,
+				Style:    style,
+				Explode:  conf.explode || conf.deepObject,
+			})
+		}
+
+		if tag := field.Tag.Get(InHeader); tag != "" {
+			params = append(params, Param{
+				Name:  parseFieldConf(tag).name,
+				In:    InHeader,
+				Type:  field.Type,
+				Style: "simple",
+			})
+		}
+
+		if tag, ok := field.Tag.Lookup(InCookie); ok {
+			name := parseFieldConf(tag).name
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			params = append(params, Param{
+				Name:  name,
+				In:    InCookie,
+				Type:  field.Type,
+				Style: "form",
+			})
+		}
+	}
+	return params
+}
+
+// isUntaggedValue reports whether field is an exported field without
+// decode tags which holds a single value (not a nested struct).
+func isUntaggedValue(field reflect.StructField) bool {
+	if !field.IsExported() || field.Anonymous {
+		return false
+	}
+	for _, tag := range []string{InPath, InQuery, InHeader, InCookie, "body", "meta"} {
+		if _, ok := field.Tag.Lookup(tag); ok {
+			return false
+		}
+	}
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		return typ == reflect.TypeOf(time.Time{})
+	case reflect.Map, reflect.Func, reflect.Chan, reflect.Interface:
+		return false
+	}
+	return true
+}
+
+// snakeCase converts field name to snake case, for example ClientID
+// to client_id and HTTPServer to http_server.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
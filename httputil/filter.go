@@ -0,0 +1,155 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	liberrors "github.com/enverbisevac/libs/errors"
+	"golang.org/x/exp/slices"
+)
+
+// Filter operators supported by FilterSpec.
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpLike = "like"
+)
+
+var sqlOperators = map[string]string{
+	OpEq:   "=",
+	OpNe:   "<>",
+	OpGt:   ">",
+	OpGte:  ">=",
+	OpLt:   "<",
+	OpLte:  "<=",
+	OpLike: "LIKE",
+}
+
+// Condition is a single comparison of a column with a value.
+type Condition struct {
+	Column string
+	Op     string
+	Value  any
+}
+
+// FilterSpec is a list of conditions joined with AND.
+type FilterSpec struct {
+	Conditions []Condition
+}
+
+// DollarPlaceholder returns PostgreSQL placeholder $n.
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// QuestionPlaceholder returns MySQL and SQLite placeholder ?.
+func QuestionPlaceholder(int) string {
+	return "?"
+}
+
+// Where returns parameterized WHERE clause, without the WHERE keyword,
+// and its arguments. placeholder returns placeholder for 1-based
+// argument index. Empty clause is returned for empty spec.
+func (s FilterSpec) Where(placeholder func(n int) string) (string, []any) {
+	if len(s.Conditions) == 0 {
+		return "", nil
+	}
+	parts := make([]string, len(s.Conditions))
+	args := make([]any, len(s.Conditions))
+	for i, c := range s.Conditions {
+		parts[i] = c.Column + " " + sqlOperators[c.Op] + " " + placeholder(i+1)
+		args[i] = c.Value
+	}
+	return strings.Join(parts, " AND "), args
+}
+
+// FilterSpecFromRequest builds FilterSpec from the request query using
+// fields of F as allow-list. Fields are matched by the query tag name,
+// column tag sets the column name (query name by default) and ops tag
+// lists allowed operators (eq by default), for example:
+//
+//	type ArticleFilter struct {
+//		Status string `query:"status"`
+//		Price  int    `query:"price" ops:"eq,gte,lte"`
+//		Title  string `query:"title" column:"a.title" ops:"like"`
+//	}
+//
+// Operator is given in brackets, ?price[gte]=5&status=open results in
+// price >= 5 AND status = 'open'. Values are converted to the field
+// type. Unknown parameters are ignored, invalid values or operators
+// which are not allowed return errors.ValidationError.
+func FilterSpecFromRequest[F any](r *http.Request) (FilterSpec, error) {
+	var spec FilterSpec
+	typ := reflect.TypeOf((*F)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return spec, fmt.Errorf("invalid filter type: %v", typ)
+	}
+
+	query := r.URL.Query()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get(InQuery)
+		if tag == "" {
+			continue
+		}
+		name := parseFieldConf(tag).name
+		column := field.Tag.Get("column")
+		if column == "" {
+			column = name
+		}
+		allowed := []string{OpEq}
+		if ops := field.Tag.Get("ops"); ops != "" {
+			allowed = strings.Split(ops, ",")
+		}
+
+		ops, err := filterOps(query, name, allowed)
+		if err != nil {
+			return FilterSpec{}, err
+		}
+		for _, op := range allowed {
+			value, ok := ops[op]
+			if !ok {
+				continue
+			}
+			v := reflect.New(field.Type).Elem()
+			if err := resolveValue(v, field.Type, value); err != nil {
+				return FilterSpec{}, liberrors.Validation("invalid value of filter %s: %v", name, err)
+			}
+			spec.Conditions = append(spec.Conditions, Condition{
+				Column: column,
+				Op:     op,
+				Value:  v.Interface(),
+			})
+		}
+	}
+	return spec, nil
+}
+
+// filterOps returns values of name and name[op] query parameters by
+// operator, operators not in allowed return error.
+func filterOps(query url.Values, name string, allowed []string) (map[string]string, error) {
+	ops := make(map[string]string)
+	if query.Has(name) {
+		ops[OpEq] = query.Get(name)
+	}
+	for op, values := range deepValues(query, name) {
+		ops[op] = values[0]
+	}
+	for op := range ops {
+		if !slices.Contains(allowed, op) {
+			return nil, liberrors.Validation("operator %s is not allowed for filter %s", op, name)
+		}
+		if _, ok := sqlOperators[op]; !ok {
+			return nil, liberrors.Validation("unknown operator %s for filter %s", op, name)
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,41 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/enverbisevac/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSpecFromRequest(t *testing.T) {
+	type articleFilter struct {
+		Status string `query:"status"`
+		Price  int    `query:"price" ops:"eq,gte,lte"`
+		Title  string `query:"title" column:"a.title" ops:"like"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?status=open&price[lte]=100&price[gte]=5&title[like]=go%25&page=2", nil)
+	spec, err := FilterSpecFromRequest[articleFilter](r)
+	assert.NoError(t, err)
+
+	clause, args := spec.Where(DollarPlaceholder)
+	assert.Equal(t, "status = $1 AND price >= $2 AND price <= $3 AND a.title LIKE $4", clause)
+	assert.Equal(t, []any{"open", 5, 100, "go%"}, args)
+
+	clause, _ = spec.Where(QuestionPlaceholder)
+	assert.Equal(t, "status = ? AND price >= ? AND price <= ? AND a.title LIKE ?", clause)
+
+	for _, query := range []string{"status[gte]=open", "price[gte]=x", "title=go"} {
+		r, _ = http.NewRequest("GET", "/?"+query, nil)
+		_, err = FilterSpecFromRequest[articleFilter](r)
+		assert.True(t, errors.IsValidation(err), query)
+	}
+
+	r, _ = http.NewRequest("GET", "/", nil)
+	spec, err = FilterSpecFromRequest[articleFilter](r)
+	assert.NoError(t, err)
+	clause, args = spec.Where(DollarPlaceholder)
+	assert.Empty(t, clause)
+	assert.Nil(t, args)
+}
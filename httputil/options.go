@@ -41,6 +41,24 @@ func WithHTTPTimeout(timeout time.Duration) ClientOptionFunc {
 	}
 }
 
+// WithMaxResponseBytes limits the size of response body read by the
+// client, ErrResponseTooLarge is returned when the limit is exceeded.
+func WithMaxResponseBytes(n int64) ClientOptionFunc {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithTypedErrors decodes error responses written by errors.JSONResponse
+// into typed errors, so errors.IsNotFound and similar work with errors
+// returned by the client. Other error bodies are returned as
+// ErrorResponse.
+func WithTypedErrors() ClientOptionFunc {
+	return func(c *Client) {
+		c.typedErrors = true
+	}
+}
+
 type RequestOption interface {
 	Apply(r *http.Request)
 }
@@ -62,3 +80,119 @@ func WithAcceptVersion(value string) RequestOptionFunc {
 		r.Header.Set("Accept", "application/json;version="+value)
 	}
 }
+
+type DecoderOption interface {
+	Apply(d *Decoder)
+}
+
+type DecoderOptionFunc func(d *Decoder)
+
+func (f DecoderOptionFunc) Apply(d *Decoder) {
+	f(d)
+}
+
+// RejectUnknownQuery makes the decoder return ErrUnknownQueryParam when
+// the request contains query parameters not mapped to any field.
+func RejectUnknownQuery() DecoderOptionFunc {
+	return func(d *Decoder) {
+		d.rejectUnknownQuery = true
+	}
+}
+
+// SnakeCaseQuery binds untagged fields from query parameters named as
+// snake case of the field name, for example ClientID from client_id.
+// Explicit query tags take precedence.
+func SnakeCaseQuery() DecoderOptionFunc {
+	return func(d *Decoder) {
+		d.snakeCaseQuery = true
+	}
+}
+
+// CaseInsensitiveQuery matches query parameters to query tag names
+// ignoring case, so ?Status=open binds to `query:"status"`. Values of
+// parameters differing only in case are all bound in request order.
+func CaseInsensitiveQuery() DecoderOptionFunc {
+	return func(d *Decoder) {
+		d.caseInsensitiveQuery = true
+	}
+}
+
+// RequireBody makes decoding of POST, PUT and PATCH requests with empty
+// body fail with errors.ValidationError (400).
+func RequireBody() DecoderOptionFunc {
+	return func(d *Decoder) {
+		d.requireBody = true
+	}
+}
+
+// FlagQuery makes bool fields true when their query parameter is
+// present without value, ?verbose sets Verbose bool `query:"verbose"`
+// to true same as ?verbose=true, ?verbose=false still sets it to
+// false. By default parameter without value fails to decode into bool.
+func FlagQuery() DecoderOptionFunc {
+	return func(d *Decoder) {
+		d.flagQuery = true
+	}
+}
+
+// LenientNumbers makes decoding of JSON bodies accept numbers for
+// string fields, {"id": 123} for ID string, and numeric strings for
+// number fields, {"count": "5"} for Count int. Decoding is strict by
+// default.
+func LenientNumbers() DecoderOptionFunc {
+	return func(d *Decoder) {
+		d.lenientNumbers = true
+	}
+}
+
+// MaxBodyBytes limits request body size, decoding of larger body fails
+// with ErrBodyTooLarge. Body size is unlimited by default, for JSON APIs
+// a limit of 1MB (1 << 20) is recommended.
+func MaxBodyBytes(n int64) DecoderOptionFunc {
+	return func(d *Decoder) {
+		d.maxBodyBytes = n
+	}
+}
+
+// WithBodyDecoder registers fn for decoding bodies in format, which can
+// be selected by the body tag, for example `body:"item,yaml"`, or by
+// the request Content-Type header matching one of contentTypes:
+//
+//	httputil.NewDecoder(chi.URLParam, httputil.WithBodyDecoder("yaml",
+//		func(r io.Reader, v any) error {
+//			return yaml.NewDecoder(r).Decode(v)
+//		}, "application/yaml"))
+//
+// Registering json or xml format replaces the default decoder.
+func WithBodyDecoder(format string, fn BodyDecoder, contentTypes ...string) DecoderOptionFunc {
+	return func(d *Decoder) {
+		if d.bodyDecoders == nil {
+			d.bodyDecoders = make(map[string]BodyDecoder)
+		}
+		d.bodyDecoders[format] = fn
+		if len(contentTypes) > 0 && d.contentTypes == nil {
+			d.contentTypes = make(map[string]string)
+		}
+		for _, contentType := range contentTypes {
+			d.contentTypes[contentType] = format
+		}
+	}
+}
+
+type CSVOption interface {
+	Apply(c *csvConfig)
+}
+
+type CSVOptionFunc func(c *csvConfig)
+
+func (f CSVOptionFunc) Apply(c *csvConfig) {
+	f(c)
+}
+
+// WithFilename makes WriteCSV send the response as attachment with
+// the filename.
+func WithFilename(name string) CSVOptionFunc {
+	return func(c *csvConfig) {
+		c.filename = name
+	}
+}
@@ -2,8 +2,11 @@
 package httputil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -16,6 +19,8 @@ var (
 	PageVar = "page"
 	// PageSizeVar specifies the query parameter name for page size
 	PageSizeVar = "per_page"
+	// TotalCountHeader specifies the response header name for total count
+	TotalCountHeader = "X-Total-Count"
 )
 
 // Pages represents a paginated list of data items.
@@ -25,6 +30,10 @@ type Pages[T any] struct {
 	PageCount  int `json:"page_count"`
 	TotalCount int `json:"total_count"`
 	Items      []T `json:"items"`
+
+	// paginator names query parameters in links, package variables
+	// are used when nil.
+	paginator *Paginator
 }
 
 // NewPages creates a new Pages instance.
@@ -33,11 +42,23 @@ type Pages[T any] struct {
 // And the total parameter specifies the total number of data items.
 // If total is less than 0, it means total is unknown.
 func NewPages[T any](page, perPage, total int) *Pages[T] {
+	return newPages[T](NewPaginator(), page, perPage, total)
+}
+
+// NewPagesWith creates a new Pages instance same as NewPages, using
+// page sizes and query parameter names of paginator.
+func NewPagesWith[T any](paginator *Paginator, page, perPage, total int) *Pages[T] {
+	p := newPages[T](paginator, page, perPage, total)
+	p.paginator = paginator
+	return p
+}
+
+func newPages[T any](paginator *Paginator, page, perPage, total int) *Pages[T] {
 	if perPage <= 0 {
-		perPage = DefaultPageSize
+		perPage = paginator.defaultPageSize
 	}
-	if perPage > MaxPageSize {
-		perPage = MaxPageSize
+	if perPage > paginator.maxPageSize {
+		perPage = paginator.maxPageSize
 	}
 	pageCount := -1
 	if total >= 0 {
@@ -64,19 +85,55 @@ func NewPagesWithItems[T any](page, perPage, total int, items []T) *Pages[T] {
 	return p
 }
 
+// MapPages returns a copy of p with items mapped by fn, for example
+// from database rows to DTOs.
+func MapPages[T, U any](p *Pages[T], fn func(T) U) *Pages[U] {
+	var items []U
+	if p.Items != nil {
+		items = make([]U, len(p.Items))
+		for i, item := range p.Items {
+			items[i] = fn(item)
+		}
+	}
+	return &Pages[U]{
+		Page:       p.Page,
+		PerPage:    p.PerPage,
+		PageCount:  p.PageCount,
+		TotalCount: p.TotalCount,
+		Items:      items,
+		paginator:  p.paginator,
+	}
+}
+
 // PagesFromRequest creates a Pages object using the query parameters found in the given HTTP request.
 // count stands for the total number of items. Use -1 if this is unknown.
 func PagesFromRequest[T any](req *http.Request, count int) *Pages[T] {
-	page := QueryParamOrDefault(req, PageVar, 1)
-	perPage := QueryParamOrDefault(req, PageSizeVar, DefaultPageSize)
+	page, perPage := NewPaginator().FromRequest(req)
 	return NewPages[T](page, perPage, count)
 }
 
+// PagesFromRequestStrict creates a Pages object same as PagesFromRequest
+// but returns DecodeError when page or page size are present and are
+// not positive integers, instead of using defaults.
+func PagesFromRequestStrict[T any](req *http.Request, count int) (*Pages[T], error) {
+	page, perPage, err := NewPaginator().FromRequestStrict(req)
+	if err != nil {
+		return nil, err
+	}
+	return NewPages[T](page, perPage, count), nil
+}
+
+// PagesFromRequestWith creates a Pages object same as PagesFromRequest,
+// using page sizes and query parameter names of paginator.
+func PagesFromRequestWith[T any](paginator *Paginator, req *http.Request, count int) *Pages[T] {
+	page, perPage := paginator.FromRequest(req)
+	return NewPagesWith[T](paginator, page, perPage, count)
+}
+
 // PagesFromRequest creates a Pages object using the query parameters found in the given HTTP request.
 // count stands for the total number of items. Use -1 if this is unknown.
 func PagesFromReqAndData[T any](req *http.Request, fn func(limit, offset int) ([]T, int64, error)) (*Pages[T], error) {
-	page := QueryParamOrDefault(req, PageVar, 1)
-	perPage := QueryParamOrDefault(req, PageSizeVar, DefaultPageSize)
+	page, perPage := NewPaginator().FromRequest(req)
 	data, total, err := fn(perPage, (page-1)*perPage)
 	if err != nil {
 		return nil, err
@@ -84,6 +141,113 @@ func PagesFromReqAndData[T any](req *http.Request, fn func(limit, offset int) ([
 	return NewPagesWithItems[T](page, perPage, int(total), data), nil
 }
 
+// ListConfig holds settings of ListHandler.
+type ListConfig struct {
+	// Decoder decodes the filter, default is a Decoder without path
+	// param function.
+	Decoder *Decoder
+	// Paginator reads page parameters, default is NewPaginator().
+	Paginator *Paginator
+	// OnEncodeError is called when writing the response fails, after
+	// the status is written.
+	OnEncodeError func(r *http.Request, err error)
+}
+
+// ListOption configures ListHandler.
+type ListOption interface {
+	Apply(c *ListConfig)
+}
+
+// ListOptionFunc is a function that configures ListHandler.
+type ListOptionFunc func(c *ListConfig)
+
+// Apply calls f(config).
+func (f ListOptionFunc) Apply(c *ListConfig) {
+	f(c)
+}
+
+// WithListDecoder sets the decoder used for the filter.
+func WithListDecoder(d *Decoder) ListOptionFunc {
+	return func(c *ListConfig) {
+		c.Decoder = d
+	}
+}
+
+// WithListPaginator sets the paginator used for page parameters.
+func WithListPaginator(p *Paginator) ListOptionFunc {
+	return func(c *ListConfig) {
+		c.Paginator = p
+	}
+}
+
+// WithEncodeErrorHandler sets the function called when writing the
+// response fails.
+func WithEncodeErrorHandler(fn func(r *http.Request, err error)) ListOptionFunc {
+	return func(c *ListConfig) {
+		c.OnEncodeError = fn
+	}
+}
+
+// ListHandler returns handler for list endpoints. It decodes and
+// validates filter F same as BindMiddleware, reads page parameters from
+// the query, calls fetch with the filter, limit and offset and writes
+// Pages[T] in format negotiated from the Accept header, for example:
+//
+//	r.Get("/articles", httputil.ListHandler(
+//		func(ctx context.Context, filter ArticleFilter, limit, offset int) ([]Article, int, error) {
+//			return repo.List(ctx, filter, limit, offset)
+//		}, httputil.WithListDecoder(decoder), httputil.WithListPaginator(paginator)))
+//
+// fetch returns items and total count, -1 if total is unknown. Link and
+// X-Total-Count headers are set on the response.
+func ListHandler[F, T any](fetch func(ctx context.Context, filter F, limit, offset int) ([]T, int, error), options ...ListOption) http.HandlerFunc {
+	config := ListConfig{}
+	for _, opt := range options {
+		opt.Apply(&config)
+	}
+	if config.Decoder == nil {
+		config.Decoder = NewDecoder(nil)
+	}
+	if config.Paginator == nil {
+		config.Paginator = NewPaginator()
+	}
+	paginator := config.Paginator
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filter F
+		if err := bind(config.Decoder, r, &filter); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		p := PagesFromRequestWith[T](paginator, r, -1)
+		items, total, err := fetch(r.Context(), filter, p.Limit(), p.Offset())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		pages := NewPagesWith[T](paginator, p.Page, p.PerPage, total)
+		pages.Items = items
+
+		pages.SetHeaders(w.Header(), pageBaseURL(r, paginator), paginator.defaultPageSize)
+		enc, contentType := negotiateEncoder(w, r)
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		if err := enc.Encode(pages); err != nil && config.OnEncodeError != nil {
+			config.OnEncodeError(r, err)
+		}
+	}
+}
+
+// pageBaseURL returns request path and query without page parameters
+// of paginator.
+func pageBaseURL(r *http.Request, paginator *Paginator) string {
+	query := r.URL.Query()
+	query.Del(paginator.pageVar)
+	query.Del(paginator.pageSizeVar)
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return u.String()
+}
+
 // Offset returns the OFFSET value that can be used in a SQL statement.
 func (p *Pages[T]) Offset() int {
 	return (p.Page - 1) * p.PerPage
@@ -97,21 +261,25 @@ func (p *Pages[T]) Limit() int {
 // BuildLinkHeader returns an HTTP header containing the links about the pagination.
 func (p *Pages[T]) BuildLinkHeader(baseURL string, defaultPerPage int) string {
 	links := p.BuildLinks(baseURL, defaultPerPage)
-	header := ""
-	if links[0] != "" {
-		header += fmt.Sprintf("<%v>; rel=\"first\", ", links[0])
-		header += fmt.Sprintf("<%v>; rel=\"prev\"", links[1])
-	}
-	if links[2] != "" {
-		if header != "" {
-			header += ", "
-		}
-		header += fmt.Sprintf("<%v>; rel=\"next\"", links[2])
-		if links[3] != "" {
-			header += fmt.Sprintf(", <%v>; rel=\"last\"", links[3])
+	rels := [4]string{"first", "prev", "next", "last"}
+	header := make([]string, 0, len(links))
+	for i, link := range links {
+		if link != "" {
+			header = append(header, fmt.Sprintf("<%v>; rel=\"%v\"", link, rels[i]))
 		}
 	}
-	return header
+	return strings.Join(header, ", ")
+}
+
+// SetHeaders sets the Link header built by BuildLinkHeader and the
+// TotalCountHeader when the total count is known.
+func (p *Pages[T]) SetHeaders(header http.Header, baseURL string, defaultPerPage int) {
+	if link := p.BuildLinkHeader(baseURL, defaultPerPage); link != "" {
+		header.Set("Link", link)
+	}
+	if p.TotalCount >= 0 {
+		header.Set(TotalCountHeader, strconv.Itoa(p.TotalCount))
+	}
 }
 
 // BuildLinks returns the first, prev, next, and last links corresponding to the pagination.
@@ -120,6 +288,11 @@ func (p *Pages[T]) BuildLinkHeader(baseURL string, defaultPerPage int) string {
 // will be empty.
 func (p *Pages[T]) BuildLinks(baseURL string, defaultPerPage int) [4]string {
 	var links [4]string
+	paginator := p.paginator
+	if paginator == nil {
+		paginator = NewPaginator()
+	}
+	pageVar, pageSizeVar := paginator.pageVar, paginator.pageSizeVar
 	pageCount := p.PageCount
 	page := p.Page
 	if pageCount >= 0 && page > pageCount {
@@ -131,19 +304,19 @@ func (p *Pages[T]) BuildLinks(baseURL string, defaultPerPage int) [4]string {
 		baseURL += "?"
 	}
 	if page > 1 {
-		links[0] = fmt.Sprintf("%v%v=%v", baseURL, PageVar, 1)
-		links[1] = fmt.Sprintf("%v%v=%v", baseURL, PageVar, page-1)
+		links[0] = fmt.Sprintf("%v%v=%v", baseURL, pageVar, 1)
+		links[1] = fmt.Sprintf("%v%v=%v", baseURL, pageVar, page-1)
 	}
 	if pageCount >= 0 && page < pageCount {
-		links[2] = fmt.Sprintf("%v%v=%v", baseURL, PageVar, page+1)
-		links[3] = fmt.Sprintf("%v%v=%v", baseURL, PageVar, pageCount)
+		links[2] = fmt.Sprintf("%v%v=%v", baseURL, pageVar, page+1)
+		links[3] = fmt.Sprintf("%v%v=%v", baseURL, pageVar, pageCount)
 	} else if pageCount < 0 {
-		links[2] = fmt.Sprintf("%v%v=%v", baseURL, PageVar, page+1)
+		links[2] = fmt.Sprintf("%v%v=%v", baseURL, pageVar, page+1)
 	}
 	if perPage := p.PerPage; perPage != defaultPerPage {
 		for i := 0; i < 4; i++ {
 			if links[i] != "" {
-				links[i] += fmt.Sprintf("&%v=%v", PageSizeVar, perPage)
+				links[i] += fmt.Sprintf("&%v=%v", pageSizeVar, perPage)
 			}
 		}
 	}
@@ -2,9 +2,13 @@ package httputil
 
 import (
 	"bytes"
+	"context"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
+	"github.com/enverbisevac/libs/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,11 +68,54 @@ func TestPages_BuildLinkHeader(t *testing.T) {
 		assert.Equal(t, test.header, p.BuildLinkHeader(baseURL, defaultPerPage), test.tag)
 	}
 
+	unknown := []struct {
+		tag    string
+		page   int
+		header string
+	}{
+		{"first page", 1, "</tokens?page=2&per_page=20>; rel=\"next\""},
+		{"second page", 2, "</tokens?page=1&per_page=20>; rel=\"first\", </tokens?page=1&per_page=20>; rel=\"prev\", </tokens?page=3&per_page=20>; rel=\"next\""},
+		{"later page", 7, "</tokens?page=1&per_page=20>; rel=\"first\", </tokens?page=6&per_page=20>; rel=\"prev\", </tokens?page=8&per_page=20>; rel=\"next\""},
+	}
+	for _, test := range unknown {
+		p := NewPages[struct{}](test.page, 20, -1)
+		header := p.BuildLinkHeader(baseURL, defaultPerPage)
+		assert.Equal(t, test.header, header, test.tag)
+		assert.NotContains(t, header, "last", test.tag)
+	}
+
 	baseURL = "/tokens?from=10"
 	p := NewPages[struct{}](1, 20, 50)
 	assert.Equal(t, "</tokens?from=10&page=2&per_page=20>; rel=\"next\", </tokens?from=10&page=3&per_page=20>; rel=\"last\"", p.BuildLinkHeader(baseURL, defaultPerPage))
 }
 
+func TestPages_SetHeaders(t *testing.T) {
+	header := http.Header{}
+	NewPages[struct{}](2, 10, 25).SetHeaders(header, "/tokens", 10)
+	assert.Equal(t, "25", header.Get(TotalCountHeader))
+	assert.Equal(t, `</tokens?page=1>; rel="first", </tokens?page=1>; rel="prev", `+
+		`</tokens?page=3>; rel="next", </tokens?page=3>; rel="last"`, header.Get("Link"))
+
+	header = http.Header{}
+	NewPages[struct{}](2, 10, -1).SetHeaders(header, "/tokens", 10)
+	assert.Empty(t, header.Values(TotalCountHeader))
+	assert.Equal(t, `</tokens?page=1>; rel="first", </tokens?page=1>; rel="prev", </tokens?page=3>; rel="next"`, header.Get("Link"))
+
+	header = http.Header{}
+	NewPages[struct{}](1, 10, 0).SetHeaders(header, "/tokens", 10)
+	assert.Equal(t, "0", header.Get(TotalCountHeader))
+	assert.Empty(t, header.Values("Link"))
+}
+
+func TestMapPages(t *testing.T) {
+	p := NewPagesWithItems(2, 2, 5, []int{3, 4})
+	got := MapPages(p, strconv.Itoa)
+	assert.Equal(t, &Pages[string]{Page: 2, PerPage: 2, PageCount: 3, TotalCount: 5, Items: []string{"3", "4"}}, got)
+
+	empty := MapPages(NewPages[int](1, 10, 0), strconv.Itoa)
+	assert.Nil(t, empty.Items)
+}
+
 func TestNewFromRequest(t *testing.T) {
 	req, _ := http.NewRequest("GET", "http://example.com?page=2&per_page=20", bytes.NewBufferString(""))
 	p := PagesFromRequest[struct{}](req, 100)
@@ -77,3 +124,79 @@ func TestNewFromRequest(t *testing.T) {
 	assert.Equal(t, 100, p.TotalCount)
 	assert.Equal(t, 5, p.PageCount)
 }
+
+func TestListHandler(t *testing.T) {
+	type filter struct {
+		Status string `query:"status"`
+	}
+	type article struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	}
+
+	var got struct {
+		filter        filter
+		limit, offset int
+	}
+	fetch := func(ctx context.Context, f filter, limit, offset int) ([]article, int, error) {
+		if f.Status == "failing" {
+			return nil, 0, errors.NotFound("status %s not found", f.Status)
+		}
+		got.filter, got.limit, got.offset = f, limit, offset
+		return []article{{ID: 3, Status: f.Status}, {ID: 4, Status: f.Status}}, 5, nil
+	}
+	handler := ListHandler(fetch)
+
+	t.Run("page", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/articles?status=open&page=2&per_page=2", nil)
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, filter{Status: "open"}, got.filter)
+		assert.Equal(t, 2, got.limit)
+		assert.Equal(t, 2, got.offset)
+		assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+		assert.Equal(t, `</articles?status=open&page=1&per_page=2>; rel="first", `+
+			`</articles?status=open&page=1&per_page=2>; rel="prev", `+
+			`</articles?status=open&page=3&per_page=2>; rel="next", `+
+			`</articles?status=open&page=3&per_page=2>; rel="last"`, w.Header().Get("Link"))
+		assert.JSONEq(t, `{"page":2,"per_page":2,"page_count":3,"total_count":5,
+			"items":[{"id":3,"status":"open"},{"id":4,"status":"open"}]}`, w.Body.String())
+	})
+
+	t.Run("paginator", func(t *testing.T) {
+		paginator := NewPaginator(WithPageVar("p"), WithPageSizeVar("size"), WithDefaultPageSize(2))
+		r := httptest.NewRequest("GET", "/articles?status=open&p=2", nil)
+		w := httptest.NewRecorder()
+
+		ListHandler(fetch, WithListPaginator(paginator))(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, got.limit)
+		assert.Equal(t, 2, got.offset)
+		assert.Equal(t, `</articles?status=open&p=1>; rel="first", `+
+			`</articles?status=open&p=1>; rel="prev", `+
+			`</articles?status=open&p=3>; rel="next", `+
+			`</articles?status=open&p=3>; rel="last"`, w.Header().Get("Link"))
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/articles?status=failing", nil)
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("encode error", func(t *testing.T) {
+		var encodeErr error
+		handler := ListHandler(func(ctx context.Context, f filter, limit, offset int) ([]chan int, int, error) {
+			return []chan int{make(chan int)}, 1, nil
+		}, WithEncodeErrorHandler(func(r *http.Request, err error) {
+			encodeErr = err
+		}))
+
+		handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/articles", nil))
+		assert.Error(t, encodeErr)
+	})
+}
@@ -0,0 +1,119 @@
+package httputil
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Paginator holds page sizes and query parameter names used for
+// pagination, so services in one binary can use different conventions
+// without changing package variables.
+type Paginator struct {
+	pageVar         string
+	pageSizeVar     string
+	defaultPageSize int
+	maxPageSize     int
+}
+
+// PaginatorOption configures a Paginator.
+type PaginatorOption interface {
+	Apply(p *Paginator)
+}
+
+// PaginatorOptionFunc is a function that configures a Paginator.
+type PaginatorOptionFunc func(p *Paginator)
+
+// Apply calls f(paginator).
+func (f PaginatorOptionFunc) Apply(p *Paginator) {
+	f(p)
+}
+
+// WithPageVar sets the query parameter name for page number.
+func WithPageVar(name string) PaginatorOptionFunc {
+	return func(p *Paginator) {
+		p.pageVar = name
+	}
+}
+
+// WithPageSizeVar sets the query parameter name for page size.
+func WithPageSizeVar(name string) PaginatorOptionFunc {
+	return func(p *Paginator) {
+		p.pageSizeVar = name
+	}
+}
+
+// WithDefaultPageSize sets the page size used when it is not requested.
+func WithDefaultPageSize(n int) PaginatorOptionFunc {
+	return func(p *Paginator) {
+		p.defaultPageSize = n
+	}
+}
+
+// WithMaxPageSize sets the maximum page size.
+func WithMaxPageSize(n int) PaginatorOptionFunc {
+	return func(p *Paginator) {
+		p.maxPageSize = n
+	}
+}
+
+// NewPaginator creates a Paginator, settings which are not configured
+// by options are taken from DefaultPageSize, MaxPageSize, PageVar and
+// PageSizeVar.
+//
+//	paginator := httputil.NewPaginator(httputil.WithPageVar("p"), httputil.WithMaxPageSize(500))
+//	pages := httputil.PagesFromRequestWith[Article](paginator, r, total)
+func NewPaginator(options ...PaginatorOption) *Paginator {
+	p := &Paginator{
+		pageVar:         PageVar,
+		pageSizeVar:     PageSizeVar,
+		defaultPageSize: DefaultPageSize,
+		maxPageSize:     MaxPageSize,
+	}
+	for _, opt := range options {
+		opt.Apply(p)
+	}
+	return p
+}
+
+// FromRequest returns page number and page size from query parameters
+// of req, defaults are used for missing or invalid values.
+func (p *Paginator) FromRequest(req *http.Request) (page, perPage int) {
+	page = QueryParamOrDefault(req, p.pageVar, 1)
+	perPage = QueryParamOrDefault(req, p.pageSizeVar, p.defaultPageSize)
+	return page, perPage
+}
+
+// FromRequestStrict returns page number and page size from query
+// parameters of req same as FromRequest, but returns DecodeError when
+// they are present and not positive integers. Page size is capped at
+// the maximum page size.
+func (p *Paginator) FromRequestStrict(req *http.Request) (page, perPage int, err error) {
+	query := req.URL.Query()
+	var fields []FieldError
+	parse := func(name string, def int) int {
+		if !query.Has(name) {
+			return def
+		}
+		n, err := strconv.Atoi(query.Get(name))
+		if err != nil {
+			fields = append(fields, FieldError{Tag: InQuery, Name: name, Err: errors.New("must be an integer")})
+			return def
+		}
+		if n < 1 {
+			fields = append(fields, FieldError{Tag: InQuery, Name: name, Err: errors.New("must be at least 1")})
+			return def
+		}
+		return n
+	}
+
+	page = parse(p.pageVar, 1)
+	perPage = parse(p.pageSizeVar, p.defaultPageSize)
+	if perPage > p.maxPageSize {
+		perPage = p.maxPageSize
+	}
+	if len(fields) > 0 {
+		return 0, 0, &DecodeError{Fields: fields}
+	}
+	return page, perPage, nil
+}
@@ -0,0 +1,65 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginator(t *testing.T) {
+	paginator := NewPaginator(WithPageVar("p"), WithPageSizeVar("size"), WithDefaultPageSize(20), WithMaxPageSize(500))
+
+	req, _ := http.NewRequest("GET", "/articles?p=2&size=1000&page=5", nil)
+	page, perPage := paginator.FromRequest(req)
+	assert.Equal(t, 2, page)
+	assert.Equal(t, 1000, perPage)
+
+	p := PagesFromRequestWith[int](paginator, req, -1)
+	assert.Equal(t, 2, p.Page)
+	assert.Equal(t, 500, p.PerPage)
+	assert.Equal(t, `</articles?p=1&size=500>; rel="first", </articles?p=1&size=500>; rel="prev", </articles?p=3&size=500>; rel="next"`,
+		p.BuildLinkHeader("/articles", 20))
+	assert.Equal(t, p.BuildLinkHeader("/articles", 20), MapPages(p, func(i int) int { return i }).BuildLinkHeader("/articles", 20))
+
+	req, _ = http.NewRequest("GET", "/articles", nil)
+	assert.Equal(t, 20, PagesFromRequestWith[int](paginator, req, 100).PerPage)
+
+	// package level functions keep using package variables
+	req, _ = http.NewRequest("GET", "/articles?p=2&page=3", nil)
+	p = PagesFromRequest[int](req, -1)
+	assert.Equal(t, 3, p.Page)
+	assert.Equal(t, DefaultPageSize, p.PerPage)
+	assert.Equal(t, `</articles?page=1>; rel="first", </articles?page=2>; rel="prev", </articles?page=4>; rel="next"`,
+		p.BuildLinkHeader("/articles", DefaultPageSize))
+}
+
+func TestPagesFromRequestStrict(t *testing.T) {
+	tests := []struct {
+		query         string
+		page, perPage int
+		err           string
+	}{
+		{query: "", page: 1, perPage: DefaultPageSize},
+		{query: "page=2&per_page=20", page: 2, perPage: 20},
+		{query: "page=2&per_page=5000", page: 2, perPage: MaxPageSize},
+		{query: "page=-3&per_page=abc", err: "query page: must be at least 1; query per_page: must be an integer"},
+		{query: "page=x", err: "query page: must be an integer"},
+		{query: "per_page=0", err: "query per_page: must be at least 1"},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/articles?"+test.query, nil)
+			p, err := PagesFromRequestStrict[int](req, -1)
+			if test.err != "" {
+				var derr *DecodeError
+				assert.ErrorAs(t, err, &derr)
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.page, p.Page)
+			assert.Equal(t, test.perPage, p.PerPage)
+		})
+	}
+}
@@ -5,16 +5,19 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	liberrors "github.com/enverbisevac/libs/errors"
 	"github.com/enverbisevac/libs/slice"
 	"github.com/enverbisevac/libs/timeutil"
 	"github.com/enverbisevac/libs/validator"
+	"golang.org/x/exp/slices"
 )
 
 type ParamTypes interface {
-	~string | ~int | ~*int | ~int64 | ~*int64 | ~bool | ~*bool | ~float64 | time.Time |
-		~[]string | ~[]int | ~[]int64 | ~[]bool | ~[]float64 | ~[]time.Time
+	~string | ~int | ~*int | ~int64 | ~*int64 | ~uint | ~uint64 | ~bool | ~*bool | ~float64 | time.Time |
+		~[]string | ~[]int | ~[]int64 | ~[]uint | ~[]uint64 | ~[]bool | ~[]float64 | ~[]time.Time | []time.Duration
 }
 
 type FromConstraint interface {
@@ -30,6 +33,18 @@ func QueryParamOrDefault[T ParamTypes, K FromConstraint](from K, param string, d
 }
 
 func QueryParam[T ParamTypes, K FromConstraint](from K, param string, validators ...validator.ValidatorFunc[T]) (T, error) {
+	return QueryParamAt(from, param, 0, validators...)
+}
+
+// QueryParamAt works same as QueryParam but scalar types are read from
+// the value of repeated param at index, negative index counts from the
+// end, so -1 reads the last value:
+//
+//	// ?sort=name&sort=id
+//	last, err := httputil.QueryParamAt[string](r, "sort", -1) // id
+//
+// Slice types read all values regardless of index.
+func QueryParamAt[T ParamTypes, K FromConstraint](from K, param string, index int, validators ...validator.ValidatorFunc[T]) (T, error) {
 	var (
 		zero   T
 		result any
@@ -51,7 +66,14 @@ func QueryParam[T ParamTypes, K FromConstraint](from K, param string, validators
 		return zero, fmt.Errorf("%s param not found in query", param)
 	}
 
-	paramValue := paramValues[0]
+	if index < 0 {
+		index += len(paramValues)
+	}
+	if index < 0 || index >= len(paramValues) {
+		return zero, fmt.Errorf("%s param has no value at index %d", param, index)
+	}
+
+	paramValue := paramValues[index]
 	if paramValue == "" {
 		return zero, fmt.Errorf("%s param value is empty", param)
 	}
@@ -64,6 +86,11 @@ func QueryParam[T ParamTypes, K FromConstraint](from K, param string, validators
 		result = int(result.(int64))
 	case int64:
 		result, err = strconv.ParseInt(paramValue, 10, 64)
+	case uint:
+		result, err = strconv.ParseUint(paramValue, 10, 0)
+		result = uint(result.(uint64))
+	case uint64:
+		result, err = strconv.ParseUint(paramValue, 10, 64)
 	case bool:
 		result, err = strconv.ParseBool(paramValue)
 	case *bool:
@@ -76,18 +103,26 @@ func QueryParam[T ParamTypes, K FromConstraint](from K, param string, validators
 		result, err = strconv.ParseFloat(paramValue, 64)
 	case time.Time:
 		result, err = timeutil.DefaultParserFunc(paramValue)
+	case time.Duration:
+		result, err = time.ParseDuration(paramValue)
 	case []string:
 		result = paramValues
 	case []int:
 		result, err = slice.StrTo[int](paramValues)
 	case []int64:
 		result, err = slice.StrTo[int64](paramValues)
+	case []uint:
+		result, err = slice.StrTo[uint](paramValues)
+	case []uint64:
+		result, err = slice.StrTo[uint64](paramValues)
 	case []float64:
 		result, err = slice.StrTo[float64](paramValues)
 	case []bool:
 		result, err = slice.StrTo[bool](paramValues)
 	case []time.Time:
 		result, err = slice.StrTo[time.Time](paramValues)
+	case []time.Duration:
+		result, err = slice.StrTo[time.Duration](paramValues)
 	default:
 		err = fmt.Errorf("%s param type not supported %T", param, zero)
 	}
@@ -103,3 +138,45 @@ func QueryParam[T ParamTypes, K FromConstraint](from K, param string, validators
 
 	return result.(T), nil
 }
+
+// SortField is a single column of the sort order parsed by ParseSort.
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// ParseSort parses comma separated sort columns from query parameter
+// param of r, - prefix sorts the column in descending order:
+//
+//	// ?sort=-created_at,name
+//	fields, err := httputil.ParseSort(r, "sort", "created_at", "name", "id")
+//	// [{created_at true} {name false}]
+//
+// Fields are returned in request order. Columns which are not in the
+// allowed list or are repeated return a validation error, so columns
+// can be used in ORDER BY clause. Missing param returns no fields.
+func ParseSort(r *http.Request, param string, allowed ...string) ([]SortField, error) {
+	var fields []SortField
+	seen := make(map[string]struct{})
+	for _, value := range r.URL.Query()[param] {
+		for _, column := range strings.Split(value, ",") {
+			column = strings.TrimSpace(column)
+			if column == "" {
+				continue
+			}
+			field := SortField{Column: column}
+			if c, ok := strings.CutPrefix(column, "-"); ok {
+				field = SortField{Column: c, Desc: true}
+			}
+			if !slices.Contains(allowed, field.Column) {
+				return nil, liberrors.Validation("sort by %s is not allowed", field.Column)
+			}
+			if _, ok := seen[field.Column]; ok {
+				return nil, liberrors.Validation("sort by %s is repeated", field.Column)
+			}
+			seen[field.Column] = struct{}{}
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
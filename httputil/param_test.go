@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/enverbisevac/libs/errors"
 	"github.com/enverbisevac/libs/timeutil"
 	"github.com/enverbisevac/libs/validator"
 )
@@ -91,3 +93,183 @@ func TestParamByName(t *testing.T) {
 		}
 	})
 }
+
+func TestQueryParam_Duration(t *testing.T) {
+	r, err := http.NewRequest("GET", "/some-url?timeout=30s&d=1s&d=2s&bad=soon", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("test time.Duration type", func(t *testing.T) {
+		got, err := QueryParam[time.Duration](r, "timeout")
+		if err != nil {
+			t.Errorf("QueryParam() error = %v", err)
+			return
+		}
+		if got != 30*time.Second {
+			t.Errorf("QueryParam() = %v, want %v", got, 30*time.Second)
+		}
+	})
+
+	t.Run("test slice of time.Duration", func(t *testing.T) {
+		got, err := QueryParam[[]time.Duration](r, "d")
+		if err != nil {
+			t.Errorf("QueryParam() error = %v", err)
+			return
+		}
+		if want := []time.Duration{time.Second, 2 * time.Second}; !reflect.DeepEqual(got, want) {
+			t.Errorf("QueryParam() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("test invalid time.Duration", func(t *testing.T) {
+		if _, err := QueryParam[time.Duration](r, "bad"); err == nil {
+			t.Errorf("QueryParam() expected error")
+		}
+		if got := QueryParamOrDefault(r, "bad", time.Minute); got != time.Minute {
+			t.Errorf("QueryParamOrDefault() = %v, want %v", got, time.Minute)
+		}
+	})
+}
+
+func TestQueryParam_Uint(t *testing.T) {
+	r, err := http.NewRequest("GET", "/some-url?id=42&size=18446744073709551615&ids=1&ids=2&neg=-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("test uint type", func(t *testing.T) {
+		got, err := QueryParam[uint](r, "id")
+		if err != nil || got != 42 {
+			t.Errorf("QueryParam() = %v, %v, want %v", got, err, 42)
+		}
+	})
+
+	t.Run("test uint64 type", func(t *testing.T) {
+		got, err := QueryParam[uint64](r, "size")
+		if err != nil || got != 18446744073709551615 {
+			t.Errorf("QueryParam() = %v, %v, want max uint64", got, err)
+		}
+	})
+
+	t.Run("test slice of uint", func(t *testing.T) {
+		got, err := QueryParam[[]uint](r, "ids")
+		if err != nil || !reflect.DeepEqual(got, []uint{1, 2}) {
+			t.Errorf("QueryParam() = %v, %v, want %v", got, err, []uint{1, 2})
+		}
+		got64, err := QueryParam[[]uint64](r, "ids")
+		if err != nil || !reflect.DeepEqual(got64, []uint64{1, 2}) {
+			t.Errorf("QueryParam() = %v, %v, want %v", got64, err, []uint64{1, 2})
+		}
+	})
+
+	t.Run("test negative uint", func(t *testing.T) {
+		if _, err := QueryParam[uint](r, "neg"); err == nil {
+			t.Errorf("QueryParam() expected error")
+		}
+		if _, err := QueryParam[[]uint64](r, "neg"); err == nil {
+			t.Errorf("QueryParam() expected error")
+		}
+	})
+
+	t.Run("test uint with validation", func(t *testing.T) {
+		got := QueryParamOrDefault(r, "id", uint(10), func(v uint) error {
+			if v > 20 {
+				return fmt.Errorf("value %v must not exceed 20", v)
+			}
+			return nil
+		})
+		if got != 10 {
+			t.Errorf("QueryParamOrDefault() = %v, want %v", got, 10)
+		}
+	})
+}
+
+func TestQueryParamAt(t *testing.T) {
+	r, err := http.NewRequest("GET", "/some-url?sort=name&sort=id&sort=created_at&n=1&n=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		index   int
+		want    string
+		wantErr bool
+	}{
+		{index: 0, want: "name"},
+		{index: 1, want: "id"},
+		{index: -1, want: "created_at"},
+		{index: -3, want: "name"},
+		{index: 3, wantErr: true},
+		{index: -4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.index), func(t *testing.T) {
+			got, err := QueryParamAt[string](r, "sort", tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryParamAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("QueryParamAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := QueryParamAt[int](r, "n", -1); err != nil || got != 2 {
+		t.Errorf("QueryParamAt() = %v, %v, want 2", got, err)
+	}
+	if got, err := QueryParamAt[[]int](r, "n", -1); err != nil || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("QueryParamAt() = %v, %v, want [1 2]", got, err)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"created_at", "name", "id"}
+	tests := []struct {
+		name    string
+		query   string
+		want    []SortField
+		wantErr bool
+	}{
+		{
+			name:  "missing",
+			query: "",
+			want:  nil,
+		},
+		{
+			name:  "request order",
+			query: "sort=-created_at,name",
+			want:  []SortField{{Column: "created_at", Desc: true}, {Column: "name"}},
+		},
+		{
+			name:  "repeated param",
+			query: "sort=name&sort=-id&sort=",
+			want:  []SortField{{Column: "name"}, {Column: "id", Desc: true}},
+		},
+		{
+			name:    "not allowed",
+			query:   "sort=name,-password",
+			wantErr: true,
+		},
+		{
+			name:    "repeated column",
+			query:   "sort=name,-name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/articles?"+tt.query, nil)
+			got, err := ParseSort(r, "sort", allowed...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.IsValidation(err) {
+				t.Errorf("ParseSort() error = %T, want validation error", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
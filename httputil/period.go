@@ -0,0 +1,126 @@
+package httputil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is an ISO8601 duration like P1DT2H or PT30M normalized to
+// time.Duration. Days are 24 hours and weeks 7 days, years and months
+// are rejected because their length is not fixed.
+type Period time.Duration
+
+// ParsePeriod parses ISO8601 duration string.
+func ParsePeriod(value string) (Period, error) {
+	s, ok := strings.CutPrefix(value, "P")
+	if !ok || s == "" || s == "T" {
+		return 0, fmt.Errorf("invalid ISO8601 period %q", value)
+	}
+
+	var (
+		total  time.Duration
+		inTime bool
+		num    string
+	)
+	for _, ch := range s {
+		switch {
+		case ch >= '0' && ch <= '9' || ch == '.' || ch == ',':
+			if ch == ',' {
+				ch = '.'
+			}
+			num += string(ch)
+			continue
+		case ch == 'T':
+			if inTime || num != "" {
+				return 0, fmt.Errorf("invalid ISO8601 period %q", value)
+			}
+			inTime = true
+			continue
+		}
+
+		if num == "" {
+			return 0, fmt.Errorf("invalid ISO8601 period %q: missing value before %c", value, ch)
+		}
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO8601 period %q: %w", value, err)
+		}
+		num = ""
+
+		var unit time.Duration
+		switch {
+		case !inTime && (ch == 'Y' || ch == 'M'):
+			return 0, fmt.Errorf("invalid ISO8601 period %q: years and months are not supported", value)
+		case !inTime && ch == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && ch == 'D':
+			unit = 24 * time.Hour
+		case inTime && ch == 'H':
+			unit = time.Hour
+		case inTime && ch == 'M':
+			unit = time.Minute
+		case inTime && ch == 'S':
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid ISO8601 period %q: unexpected designator %c", value, ch)
+		}
+		total += time.Duration(n * float64(unit))
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid ISO8601 period %q: missing designator", value)
+	}
+
+	return Period(total), nil
+}
+
+// Duration returns period as time.Duration.
+func (p Period) Duration() time.Duration {
+	return time.Duration(p)
+}
+
+// String returns period in ISO8601 format, for example PT1H30M.
+func (p Period) String() string {
+	d := time.Duration(p)
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		b.WriteString("T")
+		if h := d / time.Hour; h > 0 {
+			fmt.Fprintf(&b, "%dH", h)
+			d -= h * time.Hour
+		}
+		if m := d / time.Minute; m > 0 {
+			fmt.Fprintf(&b, "%dM", m)
+			d -= m * time.Minute
+		}
+		if d > 0 {
+			b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+		}
+	}
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p Period) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *Period) UnmarshalText(text []byte) error {
+	v, err := ParsePeriod(string(text))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
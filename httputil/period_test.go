@@ -0,0 +1,58 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "PT30M", want: 30 * time.Minute},
+		{value: "P1DT2H", want: 26 * time.Hour},
+		{value: "P2W", want: 14 * 24 * time.Hour},
+		{value: "PT1.5S", want: 1500 * time.Millisecond},
+		{value: "P1Y", wantErr: true},
+		{value: "P1M", wantErr: true},
+		{value: "PT", wantErr: true},
+		{value: "30M", wantErr: true},
+		{value: "PT30", wantErr: true},
+		{value: "PTM", wantErr: true},
+		{value: "P1H", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParsePeriod(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Duration())
+		})
+	}
+
+	assert.Equal(t, "P1DT2H30M", Period(26*time.Hour+30*time.Minute).String())
+}
+
+func TestDecode_Period(t *testing.T) {
+	type request struct {
+		Window Period  `query:"window"`
+		Max    *Period `query:"max"`
+	}
+
+	r, _ := http.NewRequest("GET", "/?window=PT30M&max=P1D", nil)
+	var req request
+	assert.NoError(t, Decode(r, pathParams(nil), &req))
+	assert.Equal(t, 30*time.Minute, req.Window.Duration())
+	assert.Equal(t, 24*time.Hour, req.Max.Duration())
+
+	r, _ = http.NewRequest("GET", "/?window=P1M", nil)
+	assert.Error(t, Decode(r, pathParams(nil), &req))
+}
@@ -1,6 +1,7 @@
 package httputil
 
 import (
+	"encoding"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -22,23 +23,107 @@ func resolveValues(field reflect.Value, typ reflect.Type, values []string) error
 // resolveValue resolves and sets the string value to appropriate type on the field
 func resolveValue(field reflect.Value, typ reflect.Type, value string) error {
 	if field.Kind() == reflect.Pointer {
+		wrapper := reflect.New(field.Type().Elem())
+		if ok, err := resolveSetter(wrapper.Elem(), value); ok {
+			if err != nil {
+				return err
+			}
+			field.Set(wrapper)
+			return nil
+		}
 		v, err := resolve(reflect.New(typ.Elem()).Elem().Interface(), value)
 		if err != nil {
 			return err
 		}
 
-		field.Set(reflect.New(typ.Elem()))
-		field.Elem().Set(reflect.ValueOf(v))
+		elem := reflect.New(typ.Elem())
+		elem.Elem().Set(reflect.ValueOf(v))
+		if err := checkEnum(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
 		return nil
 	}
+	if ok, err := resolveSetter(field, value); ok {
+		return err
+	}
 	v, err := resolve(field.Interface(), value)
 	if err != nil {
 		return err
 	}
+	if err := checkEnum(reflect.ValueOf(v), value); err != nil {
+		return err
+	}
 	field.Set(reflect.ValueOf(v))
 	return nil
 }
 
+// isSetter reports whether typ is a wrapper with Set(T) method.
+func isSetter(typ reflect.Type) bool {
+	set, ok := reflect.PointerTo(typ).MethodByName("Set")
+	return ok && set.Type.NumIn() == 2 && set.Type.NumOut() == 0
+}
+
+// resolveSetter sets value on wrapper types like optional.Type[T] or
+// nullable.Nullable[T] which have Set(T) method, empty value calls
+// SetNull() when the wrapper has one. It reports whether field is such
+// a wrapper.
+func resolveSetter(field reflect.Value, value string) (bool, error) {
+	if !field.CanAddr() {
+		return false, nil
+	}
+	ptr := field.Addr()
+	set := ptr.MethodByName("Set")
+	if !set.IsValid() || set.Type().NumIn() != 1 || set.Type().NumOut() != 0 {
+		return false, nil
+	}
+	if value == "" {
+		setNull := ptr.MethodByName("SetNull")
+		if setNull.IsValid() && setNull.Type().NumIn() == 0 {
+			setNull.Call(nil)
+			return true, nil
+		}
+	}
+	elem := reflect.New(set.Type().In(0)).Elem()
+	if err := resolveValue(elem, elem.Type(), value); err != nil {
+		return true, err
+	}
+	set.Call([]reflect.Value{elem})
+	return true, nil
+}
+
+// enumer is implemented by types which restrict allowed values, same
+// as jsonschema Enum contract.
+type enumer interface {
+	Enum() []any
+}
+
+// checkEnum returns an error when v implements enumer and it is not
+// one of the allowed values.
+func checkEnum(v reflect.Value, value string) error {
+	e, ok := v.Interface().(enumer)
+	if !ok {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		if e, ok = ptr.Interface().(enumer); !ok {
+			return nil
+		}
+	}
+
+	allowed := e.Enum()
+	for _, a := range allowed {
+		av := reflect.ValueOf(a)
+		if !av.IsValid() || !av.Type().ConvertibleTo(v.Type()) ||
+			(av.Kind() == reflect.String) != (v.Kind() == reflect.String) {
+			continue
+		}
+		if av.Convert(v.Type()).Interface() == v.Interface() {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q, allowed values are %v", value, allowed)
+}
+
 // resolve the string value to the proper type and return the value
 func resolve(t interface{}, v string) (interface{}, error) {
 	switch t.(type) {
@@ -50,6 +135,8 @@ func resolve(t interface{}, v string) (interface{}, error) {
 		return time.Parse(time.RFC3339, v)
 	case time.Duration:
 		return time.ParseDuration(v)
+	case Period:
+		return ParsePeriod(v)
 	case int:
 		i, err := strconv.ParseInt(v, 10, 32)
 		return int(i), err
@@ -89,6 +176,51 @@ func resolve(t interface{}, v string) (interface{}, error) {
 		i, err := strconv.ParseComplex(v, 64)
 		return complex64(i), err
 	default:
-		return nil, fmt.Errorf("unsupported type: %v", reflect.TypeOf(t))
+		return resolveKind(t, v)
+	}
+}
+
+// isTextUnmarshaler reports whether typ is decoded from a single value
+// by encoding.TextUnmarshaler, like net.IP.
+func isTextUnmarshaler(typ reflect.Type) bool {
+	return reflect.PointerTo(typ).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+// resolveKind resolves the string value for types implementing
+// encoding.TextUnmarshaler and named types like type Status string by
+// their underlying kind.
+func resolveKind(t interface{}, v string) (interface{}, error) {
+	typ := reflect.TypeOf(t)
+	if typ == nil {
+		return nil, fmt.Errorf("unsupported type: %v", typ)
+	}
+	ptr := reflect.New(typ)
+	if u, ok := ptr.Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(v)); err != nil {
+			return nil, err
+		}
+		return ptr.Elem().Interface(), nil
+	}
+	var (
+		value interface{}
+		err   error
+	)
+	switch typ.Kind() {
+	case reflect.String:
+		value = v
+	case reflect.Bool:
+		value, err = strconv.ParseBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err = strconv.ParseInt(v, 10, typ.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err = strconv.ParseUint(v, 10, typ.Bits())
+	case reflect.Float32, reflect.Float64:
+		value, err = strconv.ParseFloat(v, typ.Bits())
+	default:
+		return nil, fmt.Errorf("unsupported type: %v", typ)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return reflect.ValueOf(value).Convert(typ).Interface(), nil
 }
@@ -0,0 +1,97 @@
+package httputil
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HttpStatus is implemented by success types which define response
+// status code, like openapi.OK or openapi.Created.
+type HttpStatus interface {
+	HttpStatus() int
+}
+
+type encoder interface {
+	Encode(v any) error
+}
+
+// WriteResult writes value to w using encoder negotiated from the
+// request Accept header. Response status is read from status, when
+// status is nil zero value of S is used. Body is omitted for
+// http.StatusNoContent or nil value.
+func WriteResult[S HttpStatus](w http.ResponseWriter, r *http.Request, value any, status *S) error {
+	var s S
+	if status != nil {
+		s = *status
+	}
+	code := s.HttpStatus()
+
+	if code == http.StatusNoContent || value == nil {
+		w.WriteHeader(code)
+		return nil
+	}
+
+	enc, contentType := negotiateEncoder(w, r)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+
+	return enc.Encode(value)
+}
+
+// negotiateEncoder returns encoder and content type for the request
+// Accept header, json is used by default.
+func negotiateEncoder(w http.ResponseWriter, r *http.Request) (encoder, string) {
+	switch negotiate(r.Header.Get("Accept"), "application/json", "application/xml", "application/yaml") {
+	case "application/xml":
+		return xml.NewEncoder(w), "application/xml"
+	case "application/yaml":
+		return yaml.NewEncoder(w), "application/yaml"
+	default:
+		return json.NewEncoder(w), "application/json"
+	}
+}
+
+// csvConfig holds WriteCSV options.
+type csvConfig struct {
+	filename string
+}
+
+// WriteCSV writes items to w as text/csv, the first record contains
+// headers (omitted when empty) and each item is converted to a record
+// by row, for example:
+//
+//	httputil.WriteCSV(w, articles, []string{"id", "title"}, func(a Article) []string {
+//		return []string{strconv.Itoa(a.ID), a.Title}
+//	}, httputil.WithFilename("articles.csv"))
+func WriteCSV[T any](w http.ResponseWriter, items []T, headers []string, row func(T) []string, options ...CSVOption) error {
+	var conf csvConfig
+	for _, opt := range options {
+		opt.Apply(&conf)
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	if conf.filename != "" {
+		w.Header().Set("Content-Disposition",
+			mime.FormatMediaType("attachment", map[string]string{"filename": conf.filename}))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	if len(headers) > 0 {
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		if err := cw.Write(row(item)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,79 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type created struct{}
+
+func (created) HttpStatus() int {
+	return http.StatusCreated
+}
+
+type noContent struct{}
+
+func (noContent) HttpStatus() int {
+	return http.StatusNoContent
+}
+
+func TestWriteResult(t *testing.T) {
+	type article struct {
+		ID    int    `json:"id" xml:"id"`
+		Title string `json:"title" xml:"title"`
+	}
+
+	t.Run("json", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/articles", nil)
+		w := httptest.NewRecorder()
+
+		err := WriteResult(w, r, article{ID: 1, Title: "test"}, &created{})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"id":1,"title":"test"}`, w.Body.String())
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/articles", nil)
+		r.Header.Set("Accept", "application/xml")
+		w := httptest.NewRecorder()
+
+		err := WriteResult[created](w, r, article{ID: 1, Title: "test"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "<article><id>1</id><title>test</title></article>", w.Body.String())
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		r := httptest.NewRequest("DELETE", "/articles/1", nil)
+		w := httptest.NewRecorder()
+
+		err := WriteResult(w, r, article{ID: 1}, &noContent{})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	type article struct {
+		ID    int
+		Title string
+	}
+
+	w := httptest.NewRecorder()
+	err := WriteCSV(w, []article{{1, "hello"}, {2, `say "hi", world`}}, []string{"id", "title"},
+		func(a article) []string {
+			return []string{strconv.Itoa(a.ID), a.Title}
+		}, WithFilename("articles.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=articles.csv", w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id,title\n1,hello\n2,\"say \"\"hi\"\", world\"\n", w.Body.String())
+}
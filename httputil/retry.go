@@ -0,0 +1,131 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxBackoff is the longest wait between attempts when
+// RetryPolicy.MaxBackoff is not set.
+const DefaultMaxBackoff = 30 * time.Second
+
+// RetryPolicy configures retries of failed client requests.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one.
+	MaxAttempts int
+	// Backoff is the wait before the second attempt, it doubles for
+	// every next attempt up to MaxBackoff.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between attempts, including jitter.
+	// DefaultMaxBackoff is used when zero.
+	MaxBackoff time.Duration
+	// Jitter randomizes the wait by up to the given fraction, 0.2
+	// waits between 80% and 120% of the backoff.
+	Jitter float64
+	// Retryable reports whether the request should be retried, resp
+	// is nil when err is not. DefaultRetryable is used when nil.
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// WithRetry retries requests failed with network errors or retryable
+// status codes according to policy. Request bodies are buffered by the
+// client and replayed on every attempt. Waiting between attempts stops
+// when the request context is done.
+//
+//	httputil.NewClient(uri, httputil.WithRetry(httputil.RetryPolicy{
+//		MaxAttempts: 3,
+//		Backoff:     100 * time.Millisecond,
+//		Jitter:      0.2,
+//	}))
+func WithRetry(policy RetryPolicy) ClientOptionFunc {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// DefaultRetryable retries network errors, except context errors, and
+// responses with 429, 502, 503 and 504 status codes.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retry sends requests created by newRequest until the response is
+// not retryable or attempts are exhausted.
+func (c *Client) retry(ctx context.Context, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = &RetryPolicy{MaxAttempts: 1}
+	}
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.client.Do(req)
+		if attempt >= policy.MaxAttempts || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		t := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// delay returns the wait after the given failed attempt, Backoff
+// doubled for every previous attempt with jitter, capped at MaxBackoff.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+	d := p.Backoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		if d > maxBackoff/2 {
+			d = maxBackoff
+			break
+		}
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	if d = jitter(d, p.Jitter); d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
+
+// jitter returns d randomized by up to fraction of it.
+func jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || d <= 0 {
+		return d
+	}
+	delta := fraction * float64(d)
+	return d + time.Duration(delta*(2*rand.Float64()-1))
+}
@@ -0,0 +1,65 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Topicer is implemented by events which define their topic. Events
+// which don't implement it are published to the topic named by their
+// Go type, for example orders.OrderCreated.
+type Topicer interface {
+	Topic() string
+}
+
+// EventBus publishes and dispatches typed events over PubSub. Events
+// are encoded as JSON.
+type EventBus struct {
+	ps PubSub
+}
+
+// NewEventBus creates an EventBus on top of ps.
+func NewEventBus(ps PubSub) *EventBus {
+	return &EventBus{
+		ps: ps,
+	}
+}
+
+// TopicOf returns the topic used for events of type T.
+func TopicOf[T any]() string {
+	var event T
+	if t, ok := any(event).(Topicer); ok {
+		return t.Topic()
+	}
+	if t, ok := any(&event).(Topicer); ok {
+		return t.Topic()
+	}
+	return reflect.TypeOf(&event).Elem().String()
+}
+
+// On registers handler for events of type T.
+func On[T any](
+	ctx context.Context,
+	bus *EventBus,
+	handler func(event T) error,
+	options ...SubscribeOption,
+) Consumer {
+	return bus.ps.Subscribe(ctx, TopicOf[T](), func(msg *Msg) error {
+		var event T
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			return fmt.Errorf("failed to decode event from topic %s: %w", msg.Topic, err)
+		}
+		return handler(event)
+	}, options...)
+}
+
+// Emit publishes event to the topic of type T.
+func Emit[T any](ctx context.Context, bus *EventBus, event T, options ...PublishOption) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %T: %w", event, err)
+	}
+	return bus.ps.Publish(ctx, TopicOf[T](), payload, options...)
+}
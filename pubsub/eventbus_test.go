@@ -0,0 +1,64 @@
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enverbisevac/libs/pubsub"
+	"github.com/enverbisevac/libs/pubsub/inmem"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCreated struct {
+	ID    int     `json:"id"`
+	Total float64 `json:"total"`
+}
+
+type orderShipped struct {
+	ID      int    `json:"id"`
+	Carrier string `json:"carrier"`
+}
+
+func (orderShipped) Topic() string {
+	return "orders.shipped"
+}
+
+func TestEventBus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := pubsub.NewEventBus(inmem.New(inmem.WithSendTimeout(time.Second)))
+
+	created := make(chan orderCreated, 1)
+	shipped := make(chan orderShipped, 1)
+
+	pubsub.On(ctx, bus, func(event orderCreated) error {
+		created <- event
+		return nil
+	})
+	pubsub.On(ctx, bus, func(event orderShipped) error {
+		shipped <- event
+		return nil
+	})
+
+	assert.NoError(t, pubsub.Emit(ctx, bus, orderShipped{ID: 1, Carrier: "ups"}))
+	assert.NoError(t, pubsub.Emit(ctx, bus, orderCreated{ID: 2, Total: 9.5}))
+
+	select {
+	case event := <-created:
+		assert.Equal(t, orderCreated{ID: 2, Total: 9.5}, event)
+	case <-time.After(time.Second):
+		t.Fatal("orderCreated was not dispatched")
+	}
+
+	select {
+	case event := <-shipped:
+		assert.Equal(t, orderShipped{ID: 1, Carrier: "ups"}, event)
+	case <-time.After(time.Second):
+		t.Fatal("orderShipped was not dispatched")
+	}
+
+	assert.Equal(t, "pubsub_test.orderCreated", pubsub.TopicOf[orderCreated]())
+	assert.Equal(t, "orders.shipped", pubsub.TopicOf[orderShipped]())
+}
@@ -8,6 +8,7 @@ type Config struct {
 
 	SendTimeout time.Duration
 	ChannelSize int
+	MessageTTL  time.Duration
 }
 
 // An Option configures a pubsub instance.
@@ -52,3 +53,14 @@ func WithSize(value int) Option {
 		m.ChannelSize = value
 	})
 }
+
+// WithMessageTTL specifies how long published messages stay relevant,
+// messages which wait in the subscriber buffer longer are dropped
+// instead of handled. Messages received with SubscribeChan are not
+// dropped, their Time can be checked instead. Zero TTL, the default,
+// keeps all messages.
+func WithMessageTTL(value time.Duration) Option {
+	return OptionFunc(func(m *Config) {
+		m.MessageTTL = value
+	})
+}
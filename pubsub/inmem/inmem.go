@@ -16,6 +16,8 @@ var (
 	ErrClosed = errors.New("pubsub: subscriber is closed")
 )
 
+var _ pubsub.PubSub = (*PubSub)(nil)
+
 type PubSub struct {
 	config   Config
 	mutex    sync.Mutex
@@ -64,10 +66,16 @@ func (ps *PubSub) subscribe(
 	// create subscriber and map it to the registry
 	subscriber := &inMemorySubscriber{
 		config: &config,
+		ttl:    ps.config.MessageTTL,
+		done:   make(chan struct{}),
+	}
+	subscriber.unregister = func() {
+		ps.unregister(subscriber)
 	}
 
 	config.Topics = append(config.Topics, topic)
 	subscriber.topics = subscriber.formatTopics(config.Topics...)
+	subscriber.startChannel()
 
 	// register subscriber
 	ps.registry = append(ps.registry, subscriber)
@@ -83,11 +91,26 @@ func (ps *PubSub) Subscribe(
 	options ...pubsub.SubscribeOption,
 ) pubsub.Consumer {
 	subscriber := ps.subscribe(ctx, topic, options...)
-	subscriber.handler = handler
+	subscriber.handler = subscriber.config.Chain(handler)
 	go subscriber.start(ctx)
 	return subscriber
 }
 
+// Run subscribes handler to the topic and blocks processing messages
+// until ctx is done. When pubsub.WithStopOnError option is used, Run
+// returns the first error returned by handler.
+func (ps *PubSub) Run(
+	ctx context.Context,
+	topic string,
+	handler func(payload *pubsub.Msg) error,
+	options ...pubsub.SubscribeOption,
+) error {
+	subscriber := ps.subscribe(ctx, topic, options...)
+	subscriber.handler = subscriber.config.Chain(handler)
+	defer subscriber.Close()
+	return subscriber.run(ctx)
+}
+
 func (ps *PubSub) SubscribeChan(
 	ctx context.Context,
 	topic string,
@@ -100,7 +123,10 @@ func (ps *PubSub) SubscribeChan(
 // Publish event to message broker with payload.
 func (ps *PubSub) Publish(ctx context.Context, topic string, payload []byte, opts ...pubsub.PublishOption) error {
 	log := logr.FromContextOrDiscard(ctx)
-	if len(ps.registry) == 0 {
+	ps.mutex.Lock()
+	registry := slices.Clone(ps.registry)
+	ps.mutex.Unlock()
+	if len(registry) == 0 {
 		log.V(1).Info("in pubsub Publish: no subscribers registered")
 		return nil
 	}
@@ -113,19 +139,29 @@ func (ps *PubSub) Publish(ctx context.Context, topic string, payload []byte, opt
 	}
 
 	topic = pubsub.FormatTopic(pubConfig.App, pubConfig.Namespace, topic)
+	now := time.Now()
 	wg := sync.WaitGroup{}
-	for _, sub := range ps.registry {
+	for _, sub := range registry {
 		if slices.Contains(sub.topics, topic) && !sub.isClosed() {
 			wg.Add(1)
 			go func(subscriber *inMemorySubscriber) {
 				defer wg.Done()
+				// channel is closed under write lock, send under read
+				// lock so it is not closed while sending
+				subscriber.mutex.RLock()
+				defer subscriber.mutex.RUnlock()
+				if subscriber.closed {
+					return
+				}
 				// timer is based on subscriber data
 				t := time.NewTimer(subscriber.config.SendTimeout)
 				defer t.Stop()
 				select {
 				case <-ctx.Done():
 					return
-				case subscriber.channel <- &pubsub.Msg{Topic: topic, Payload: payload}:
+				case <-subscriber.done:
+					return
+				case subscriber.channel <- &pubsub.Msg{Topic: topic, Payload: payload, Headers: pubConfig.Headers, Time: now}:
 					log.V(1).Info(fmt.Sprintf("in pubsub Publish: message %v sent to topic %s", string(payload), topic))
 				case <-t.C:
 					// channel is full for topic (message is dropped)
@@ -143,8 +179,20 @@ func (ps *PubSub) Publish(ctx context.Context, topic string, payload []byte, opt
 	return nil
 }
 
+// unregister removes subscriber from the registry.
+func (ps *PubSub) unregister(subscriber *inMemorySubscriber) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	if i := slices.Index(ps.registry, subscriber); i >= 0 {
+		ps.registry = slices.Delete(ps.registry, i, i+1)
+	}
+}
+
 func (r *PubSub) Close(_ context.Context) error {
-	for _, subscriber := range r.registry {
+	r.mutex.Lock()
+	registry := slices.Clone(r.registry)
+	r.mutex.Unlock()
+	for _, subscriber := range registry {
 		if err := subscriber.Close(); err != nil {
 			return err
 		}
@@ -154,27 +202,48 @@ func (r *PubSub) Close(_ context.Context) error {
 
 type inMemorySubscriber struct {
 	config  *pubsub.SubscribeConfig
+	ttl     time.Duration
 	handler func(*pubsub.Msg) error
 	channel chan *pubsub.Msg
-	once    sync.Once
-	mutex   sync.RWMutex
-	topics  []string
-	closed  bool
+	// done is closed first on Close, to release publishers blocked
+	// on sending while holding the read lock.
+	done       chan struct{}
+	once       sync.Once
+	mutex      sync.RWMutex
+	topics     []string
+	closed     bool
+	unregister func()
 }
 
 func (s *inMemorySubscriber) start(ctx context.Context) {
 	log := logr.FromContextOrDiscard(ctx)
-	s.startChannel()
+	if err := s.run(ctx); err != nil {
+		log.Error(err, "in pubsub start: subscriber stopped")
+	}
+}
+
+// run processes messages until ctx is done or the channel is closed.
+func (s *inMemorySubscriber) run(ctx context.Context) error {
+	log := logr.FromContextOrDiscard(ctx)
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return nil
 		case msg, ok := <-s.channel:
 			if !ok {
-				return
+				return nil
+			}
+			if s.ttl > 0 && time.Since(msg.Time) > s.ttl {
+				log.V(1).Info(fmt.Sprintf("in pubsub start: message on topic %s expired (message is dropped)", msg.Topic))
+				continue
 			}
 			if err := s.handler(msg); err != nil {
-				// TODO: bump err to caller
+				if dlqErr := s.config.PublishDeadLetter(ctx, msg, err); dlqErr != nil {
+					log.Error(dlqErr, "in pubsub start: failed to publish message to dead letter topic")
+				}
+				if s.config.StopOnError {
+					return err
+				}
 				log.Error(err, "in pubsub start: error while running handler for topic")
 			}
 		}
@@ -212,16 +281,20 @@ func (s *inMemorySubscriber) Unsubscribe(_ context.Context, topics ...string) er
 }
 
 func (s *inMemorySubscriber) Close() error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.once.Do(func() {
+		close(s.done)
+	})
 
+	s.mutex.Lock()
 	if s.closed {
+		s.mutex.Unlock()
 		return ErrClosed
 	}
 	s.closed = true
-	s.once.Do(func() {
-		close(s.channel)
-	})
+	close(s.channel)
+	s.mutex.Unlock()
+
+	s.unregister()
 	return nil
 }
 
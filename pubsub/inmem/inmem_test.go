@@ -0,0 +1,215 @@
+package inmem
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/enverbisevac/libs/pubsub"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPubSub_Run(t *testing.T) {
+	t.Run("context cancel unblocks run", func(t *testing.T) {
+		ps := New(WithSendTimeout(time.Second))
+		ctx, cancel := context.WithCancel(context.Background())
+
+		received := make(chan string, 1)
+		done := make(chan error)
+		go func() {
+			done <- ps.Run(ctx, "orders", func(msg *pubsub.Msg) error {
+				received <- string(msg.Payload)
+				return nil
+			})
+		}()
+
+		assert.Eventually(t, func() bool {
+			_ = ps.Publish(ctx, "orders", []byte("created"))
+			select {
+			case payload := <-received:
+				return payload == "created"
+			default:
+				return false
+			}
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Run did not return after context cancel")
+		}
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		ps := New(WithSendTimeout(time.Second))
+		ctx := context.Background()
+		errHandler := errors.New("handler failed")
+
+		done := make(chan error)
+		go func() {
+			done <- ps.Run(ctx, "orders", func(msg *pubsub.Msg) error {
+				return errHandler
+			}, pubsub.WithStopOnError())
+		}()
+
+		assert.Eventually(t, func() bool {
+			_ = ps.Publish(ctx, "orders", []byte("created"))
+			select {
+			case err := <-done:
+				return errors.Is(err, errHandler)
+			default:
+				return false
+			}
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("publish while run returns", func(t *testing.T) {
+		ps := New(WithSendTimeout(time.Second), WithSize(1))
+		ctx := context.Background()
+
+		stop := make(chan struct{})
+		published := make(chan struct{})
+		go func() {
+			defer close(published)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_ = ps.Publish(ctx, "orders", []byte("created"))
+				}
+			}
+		}()
+
+		for i := 0; i < 50; i++ {
+			runCtx, cancel := context.WithCancel(ctx)
+			done := make(chan error)
+			go func() {
+				done <- ps.Run(runCtx, "orders", func(msg *pubsub.Msg) error {
+					return nil
+				})
+			}()
+			time.Sleep(time.Millisecond)
+			cancel()
+			assert.NoError(t, <-done)
+		}
+		close(stop)
+		<-published
+
+		ps.mutex.Lock()
+		defer ps.mutex.Unlock()
+		assert.Empty(t, ps.registry)
+	})
+}
+
+func TestPubSub_DeadLetter(t *testing.T) {
+	ps := New(WithSendTimeout(time.Second))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, dlq := ps.SubscribeChan(ctx, "orders.dlq")
+
+	ps.Subscribe(ctx, "orders", func(msg *pubsub.Msg) error {
+		return errors.New("invalid order")
+	}, pubsub.WithDeadLetter(ps, "orders.dlq"))
+
+	assert.NoError(t, ps.Publish(ctx, "orders", []byte("created")))
+
+	select {
+	case msg := <-dlq:
+		assert.Equal(t, "app:default:orders.dlq", msg.Topic)
+		assert.Equal(t, []byte("created"), msg.Payload)
+		assert.Equal(t, "invalid order", msg.Headers[pubsub.HeaderError])
+		assert.Equal(t, "app:default:orders", msg.Headers[pubsub.HeaderTopic])
+	case <-time.After(time.Second):
+		t.Fatal("message was not published to dead letter topic")
+	}
+}
+
+func TestPubSub_Middleware(t *testing.T) {
+	ps := New(WithSendTimeout(time.Second))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logged := make(chan string, 1)
+	log := funcr.New(func(prefix, args string) {
+		select {
+		case logged <- args:
+		default:
+		}
+	}, funcr.Options{})
+
+	handled := make(chan string, 1)
+	consumer := ps.Subscribe(ctx, "orders", func(msg *pubsub.Msg) error {
+		if string(msg.Payload) == "boom" {
+			panic("boom")
+		}
+		handled <- string(msg.Payload)
+		return nil
+	}, pubsub.WithMiddleware(pubsub.LogErrors(log), pubsub.Recover()))
+	defer consumer.Close()
+
+	assert.NoError(t, ps.Publish(ctx, "orders", []byte("boom")))
+	select {
+	case args := <-logged:
+		assert.Contains(t, args, `"error"="pubsub: handler panic on topic app:default:orders: boom"`)
+		assert.Contains(t, args, `"stack"="goroutine `)
+	case <-time.After(time.Second):
+		t.Fatal("panic was not logged")
+	}
+
+	assert.NoError(t, ps.Publish(ctx, "orders", []byte("created")))
+	select {
+	case payload := <-handled:
+		assert.Equal(t, "created", payload)
+	case <-time.After(time.Second):
+		t.Fatal("subscriber stopped after panic")
+	}
+
+	err := pubsub.Recover()(func(msg *pubsub.Msg) error {
+		panic("boom")
+	})(&pubsub.Msg{Topic: "orders"})
+	assert.EqualError(t, err, "pubsub: handler panic on topic orders: boom")
+	var perr *pubsub.PanicError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.NotEmpty(t, perr.Stack)
+	}
+}
+
+func TestPubSub_MessageTTL(t *testing.T) {
+	ps := New(WithSendTimeout(time.Second), WithMessageTTL(50*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	handled := make(chan string, 3)
+	consumer := ps.Subscribe(ctx, "prices", func(msg *pubsub.Msg) error {
+		handled <- string(msg.Payload)
+		if string(msg.Payload) == "first" {
+			<-release
+		}
+		return nil
+	})
+	defer consumer.Close()
+
+	assert.NoError(t, ps.Publish(ctx, "prices", []byte("first")))
+	assert.Equal(t, "first", <-handled)
+
+	// stale waits in the buffer while the handler is busy.
+	assert.NoError(t, ps.Publish(ctx, "prices", []byte("stale")))
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	assert.NoError(t, ps.Publish(ctx, "prices", []byte("fresh")))
+	select {
+	case payload := <-handled:
+		assert.Equal(t, "fresh", payload)
+	case <-time.After(time.Second):
+		t.Fatal("fresh message was not handled")
+	}
+}
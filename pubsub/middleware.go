@@ -0,0 +1,84 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/go-logr/logr"
+)
+
+// Handler processes a message received by a subscriber.
+type Handler func(msg *Msg) error
+
+// HandlerMiddleware wraps handler with cross-cutting concerns like
+// panic recovery, logging or metrics.
+type HandlerMiddleware func(next Handler) Handler
+
+// WithMiddleware wraps subscription handler with middleware, the first
+// middleware is the outermost one:
+//
+//	ps.Subscribe(ctx, "orders", handler, pubsub.WithMiddleware(pubsub.LogErrors(log), pubsub.Recover()))
+//
+// is equivalent to LogErrors(log)(Recover()(handler)). Multiple options
+// append middleware.
+func WithMiddleware(middleware ...HandlerMiddleware) SubscribeOption {
+	return SubscribeOptionFunc(func(c *SubscribeConfig) {
+		c.Middleware = append(c.Middleware, middleware...)
+	})
+}
+
+// Chain returns handler wrapped with configured middleware.
+func (c *SubscribeConfig) Chain(handler Handler) Handler {
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		handler = c.Middleware[i](handler)
+	}
+	return handler
+}
+
+// PanicError is returned by Recover when handler panics. Stack is
+// not part of the error message, so it is not copied to places like
+// dead letter headers.
+type PanicError struct {
+	Topic string
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("pubsub: handler panic on topic %s: %v", e.Topic, e.Value)
+}
+
+// Recover returns middleware which recovers from handler panic and
+// returns it as *PanicError, so the message is handled as failed and
+// the subscriber keeps running.
+func Recover() HandlerMiddleware {
+	return func(next Handler) Handler {
+		return func(msg *Msg) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Topic: msg.Topic, Value: r, Stack: debug.Stack()}
+				}
+			}()
+			return next(msg)
+		}
+	}
+}
+
+// LogErrors returns middleware which logs errors returned by handler,
+// errors are passed on unchanged. Stack of PanicError is logged too.
+func LogErrors(log logr.Logger) HandlerMiddleware {
+	return func(next Handler) Handler {
+		return func(msg *Msg) error {
+			err := next(msg)
+			var perr *PanicError
+			switch {
+			case errors.As(err, &perr):
+				log.Error(err, "handler failed", "topic", msg.Topic, "stack", string(perr.Stack))
+			case err != nil:
+				log.Error(err, "handler failed", "topic", msg.Topic)
+			}
+			return err
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package pubsub
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type PublishConfig struct {
 	App       string
 	Namespace string
+	Headers   map[string]string
 }
 
 func (c *PublishConfig) Apply(pc *PublishConfig) {
@@ -40,6 +44,16 @@ func WithPublishNamespace(value string) PublishOption {
 	})
 }
 
+// WithPublishHeader adds header to the published message.
+func WithPublishHeader(key, value string) PublishOption {
+	return PublishOptionFunc(func(c *PublishConfig) {
+		if c.Headers == nil {
+			c.Headers = make(map[string]string)
+		}
+		c.Headers[key] = value
+	})
+}
+
 type SubscribeConfig struct {
 	Topics         []string
 	App            string
@@ -47,6 +61,32 @@ type SubscribeConfig struct {
 	HealthInterval time.Duration
 	SendTimeout    time.Duration
 	ChannelSize    int
+	StopOnError    bool
+
+	DeadLetterPublisher Publisher
+	DeadLetterTopic     string
+
+	Middleware []HandlerMiddleware
+}
+
+// PublishDeadLetter publishes msg to the dead letter topic with the
+// cause in HeaderError. It does nothing when dead letter is not
+// configured.
+func (c *SubscribeConfig) PublishDeadLetter(ctx context.Context, msg *Msg, cause error) error {
+	if c.DeadLetterPublisher == nil || c.DeadLetterTopic == "" {
+		return nil
+	}
+	options := make([]PublishOption, 0, len(msg.Headers)+4)
+	for key, value := range msg.Headers {
+		options = append(options, WithPublishHeader(key, value))
+	}
+	options = append(options,
+		WithPublishApp(c.App),
+		WithPublishNamespace(c.Namespace),
+		WithPublishHeader(HeaderTopic, msg.Topic),
+		WithPublishHeader(HeaderError, cause.Error()),
+	)
+	return c.DeadLetterPublisher.Publish(ctx, c.DeadLetterTopic, msg.Payload, options...)
 }
 
 // SubscribeOption configures a subscription config.
@@ -103,6 +143,23 @@ func WithChannelSize(value int) SubscribeOption {
 	})
 }
 
+// WithStopOnError stops processing messages when the handler returns
+// an error. Blocking consumers return the handler error.
+func WithStopOnError() SubscribeOption {
+	return SubscribeOptionFunc(func(c *SubscribeConfig) {
+		c.StopOnError = true
+	})
+}
+
+// WithDeadLetter republishes messages for which handler returned an
+// error to the topic using publisher.
+func WithDeadLetter(publisher Publisher, topic string) SubscribeOption {
+	return SubscribeOptionFunc(func(c *SubscribeConfig) {
+		c.DeadLetterPublisher = publisher
+		c.DeadLetterTopic = topic
+	})
+}
+
 func FormatTopic(app, ns, topic string) string {
 	return app + ":" + ns + ":" + topic
 }
@@ -1,10 +1,27 @@
 package pubsub
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// Message header keys set by the library.
+const (
+	// HeaderError holds handler error of dead lettered message.
+	HeaderError = "error"
+	// HeaderTopic holds original topic of dead lettered message.
+	HeaderTopic = "topic"
+)
 
 type Msg struct {
 	Topic   string
 	Payload []byte
+	// Headers are message metadata, they are delivered only by
+	// backends which support them (inmem).
+	Headers map[string]string
+	// Time is when the message was published, it is set only by
+	// backends which support it (inmem).
+	Time time.Time
 }
 
 type Publisher interface {
@@ -25,3 +42,9 @@ type Subscriber interface {
 	SubscribeChan(ctx context.Context, topic string,
 		options ...SubscribeOption) (Consumer, <-chan *Msg)
 }
+
+// PubSub publishes and subscribes to messages.
+type PubSub interface {
+	Publisher
+	Subscriber
+}
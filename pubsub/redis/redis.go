@@ -15,6 +15,8 @@ import (
 type RedisPubSub interface {
 }
 
+var _ pubsub.PubSub = (*PubSub)(nil)
+
 type PubSub struct {
 	config   Config
 	client   redis.UniversalClient
@@ -28,7 +30,7 @@ func New(client redis.UniversalClient, options ...Option) *PubSub {
 		App:            "app",
 		Namespace:      "default",
 		HealthInterval: 3 * time.Second,
-		SendTimeout:    60,
+		SendTimeout:    time.Minute,
 		ChannelSize:    100,
 	}
 
@@ -74,9 +76,6 @@ func (ps *PubSub) subscribe(
 	topics := subscriber.formatTopics(config.Topics...)
 	subscriber.rdb = ps.client.Subscribe(ctx, topics...)
 
-	// start subscriber
-	go subscriber.start(ctx)
-
 	// register subscriber
 	ps.registry = append(ps.registry, subscriber)
 
@@ -91,7 +90,7 @@ func (ps *PubSub) Subscribe(
 	options ...pubsub.SubscribeOption,
 ) pubsub.Consumer {
 	subscriber := ps.subscribe(ctx, topic, options...)
-	subscriber.handler = handler
+	subscriber.handler = subscriber.config.Chain(handler)
 	go subscriber.start(ctx)
 	return subscriber
 }
@@ -104,20 +103,15 @@ func (ps *PubSub) SubscribeChan(
 	output := make(chan *pubsub.Msg)
 
 	subscriber := ps.subscribe(ctx, topic, options...)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case ch := <-subscriber.rdb.Channel():
-				output <- &pubsub.Msg{
-					Topic:   ch.Channel,
-					Payload: []byte(ch.Payload),
-				}
-			}
+	subscriber.handler = func(msg *pubsub.Msg) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case output <- msg:
+			return nil
 		}
-	}()
+	}
+	go subscriber.start(ctx)
 
 	return subscriber, output
 }
@@ -158,9 +152,20 @@ type redisSubscriber struct {
 	handler func(msg *pubsub.Msg) error
 }
 
+// start receives messages until ctx is done or subscriber is closed.
+// The underlying redis.PubSub reconnects and re-issues all subscriptions,
+// including ones added later with Subscribe, when the connection fails.
+// A dropped idle connection is detected by the ping sent when nothing is
+// received within HealthInterval, so HealthInterval bounds how long the
+// subscriber can stay silently disconnected.
+//
+// To verify manually run redis-server, subscribe, restart redis-server
+// and publish again: the handler receives messages published after
+// the restart within HealthInterval.
 func (s *redisSubscriber) start(ctx context.Context) {
 	log := logr.FromContextOrDiscard(ctx)
-	// Go channel which receives messages.
+	// Go channel which receives messages. It must be the only call of
+	// Channel, options are applied only by the first one.
 	ch := s.rdb.Channel(
 		redis.WithChannelHealthCheckInterval(s.config.HealthInterval),
 		redis.WithChannelSendTimeout(s.config.SendTimeout),
@@ -175,11 +180,18 @@ func (s *redisSubscriber) start(ctx context.Context) {
 				log.Info("redis channel was closed")
 				return
 			}
-			if err := s.handler(&pubsub.Msg{
+			m := &pubsub.Msg{
 				Topic:   msg.Channel,
 				Payload: []byte(msg.Payload),
-			}); err != nil {
+			}
+			if err := s.handler(m); err != nil {
 				log.Error(err, "received an error from handler function")
+				if dlqErr := s.config.PublishDeadLetter(ctx, m, err); dlqErr != nil {
+					log.Error(dlqErr, "failed to publish message to dead letter topic")
+				}
+				if s.config.StopOnError {
+					return
+				}
 			}
 		}
 	}
@@ -7,7 +7,7 @@ import (
 	"github.com/enverbisevac/libs/timeutil"
 )
 
-func StrTo[T ~int | ~int64 | ~float64 | ~bool | time.Time](slice []string) ([]T, error) {
+func StrTo[T ~int | ~int64 | ~uint | ~uint64 | ~float64 | ~bool | time.Time](slice []string) ([]T, error) {
 	var (
 		val T
 		f   func(str string) (any, error)
@@ -23,6 +23,19 @@ func StrTo[T ~int | ~int64 | ~float64 | ~bool | time.Time](slice []string) ([]T,
 		f = func(str string) (any, error) {
 			return strconv.ParseInt(str, 10, 64)
 		}
+	case uint:
+		f = func(str string) (any, error) {
+			v, err := strconv.ParseUint(str, 10, 0)
+			return uint(v), err
+		}
+	case uint64:
+		f = func(str string) (any, error) {
+			return strconv.ParseUint(str, 10, 64)
+		}
+	case time.Duration:
+		f = func(str string) (any, error) {
+			return time.ParseDuration(str)
+		}
 	case float64:
 		f = func(str string) (any, error) {
 			return strconv.ParseFloat(str, 64)
@@ -28,6 +28,8 @@ import (
 	"golang.org/x/exp/constraints"
 )
 
+var RgxHexColor = regexp.MustCompile("^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$")
+
 var RgxEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
 func NotBlank(value string) bool {
@@ -103,3 +105,37 @@ func IsURL(value string) bool {
 
 	return u.Scheme != "" && u.Host != ""
 }
+
+// IsCreditCard checks card number using Luhn algorithm, spaces and
+// dashes are ignored.
+func IsCreditCard(value string) bool {
+	var (
+		sum    int
+		digits int
+	)
+	for i := len(value) - 1; i >= 0; i-- {
+		c := value[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+
+	return digits >= 12 && digits <= 19 && sum%10 == 0
+}
+
+// IsHexColor checks if value is #rgb or #rrggbb color.
+func IsHexColor(value string) bool {
+	return RgxHexColor.MatchString(value)
+}
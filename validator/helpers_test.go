@@ -0,0 +1,25 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCreditCard(t *testing.T) {
+	assert.True(t, IsCreditCard("4111111111111111"))
+	assert.True(t, IsCreditCard("4111 1111 1111 1111"))
+	assert.True(t, IsCreditCard("5500-0000-0000-0004"))
+	assert.False(t, IsCreditCard("4111111111111112"))
+	assert.False(t, IsCreditCard("4111a11111111111"))
+	assert.False(t, IsCreditCard("0"))
+	assert.False(t, IsCreditCard(""))
+}
+
+func TestIsHexColor(t *testing.T) {
+	assert.True(t, IsHexColor("#fff"))
+	assert.True(t, IsHexColor("#1A2b3C"))
+	assert.False(t, IsHexColor("fff"))
+	assert.False(t, IsHexColor("#ffff"))
+	assert.False(t, IsHexColor("#ggg"))
+}
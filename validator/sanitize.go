@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Sanitizers maps names used in the `sanitize` struct tag to
+// sanitizer functions. Custom sanitizers can be registered by
+// adding them to the map.
+var Sanitizers = map[string]func(string) string{
+	"trim":  Trim,
+	"lower": Lowercase,
+	"upper": strings.ToUpper,
+	"email": LowerEmail,
+}
+
+// Trim removes leading and trailing white space.
+func Trim(value string) string {
+	return strings.TrimSpace(value)
+}
+
+// Lowercase returns value with all letters mapped to lower case.
+func Lowercase(value string) string {
+	return strings.ToLower(value)
+}
+
+// LowerEmail normalizes an email address by trimming white space
+// and converting it to lower case.
+func LowerEmail(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// Sanitize applies fns in order on the value pointed by value. Sanitize
+// should be called before Validate so rules see the normalized value.
+func Sanitize[T any](value *T, fns ...func(T) T) {
+	if value == nil {
+		return
+	}
+	for _, fn := range fns {
+		*value = fn(*value)
+	}
+}
+
+// SanitizeStruct applies sanitizers listed in the `sanitize` tag of
+// string fields, for example:
+//
+//	type Signup struct {
+//		Email string `sanitize:"trim,lower"`
+//	}
+//
+// v must be a pointer to struct. Nested structs are sanitized too.
+func SanitizeStruct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sanitize: expected pointer to struct, got %T", v)
+	}
+	return sanitizeStruct(rv.Elem())
+}
+
+func sanitizeStruct(rv reflect.Value) error {
+	typ := rv.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		field := rv.Field(i)
+
+		if field.Kind() == reflect.Struct {
+			if err := sanitizeStruct(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag := sf.Tag.Get("sanitize")
+		if tag == "" {
+			continue
+		}
+
+		if field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.String {
+			return fmt.Errorf("sanitize: field %s is not a string", sf.Name)
+		}
+
+		value := field.String()
+		for _, name := range strings.Split(tag, ",") {
+			fn, ok := Sanitizers[strings.TrimSpace(name)]
+			if !ok {
+				return fmt.Errorf("sanitize: unknown sanitizer %q on field %s", name, sf.Name)
+			}
+			value = fn(value)
+		}
+		field.SetString(value)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	email := "  John.Doe@Example.COM "
+
+	Sanitize(&email, Trim, Lowercase)
+	assert.Equal(t, "john.doe@example.com", email)
+
+	err := Validate(email, func(v string) error {
+		if !IsEmail(v) {
+			return errors.New("invalid email")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestSanitizeStruct(t *testing.T) {
+	type Profile struct {
+		Nickname *string `sanitize:"trim"`
+	}
+	type signup struct {
+		Profile
+		Email string `sanitize:"trim,lower"`
+		Name  string `sanitize:"trim"`
+		Raw   string
+	}
+
+	nick := " nick "
+	in := signup{
+		Profile: Profile{Nickname: &nick},
+		Email:   " Admin@Example.com ",
+		Name:    "  Jane ",
+		Raw:     " raw ",
+	}
+
+	// without sanitizing the email rule fails
+	assert.False(t, IsEmail(in.Email))
+
+	assert.NoError(t, SanitizeStruct(&in))
+	assert.Equal(t, "admin@example.com", in.Email)
+	assert.Equal(t, "Jane", in.Name)
+	assert.Equal(t, " raw ", in.Raw)
+	assert.Equal(t, "nick", *in.Nickname)
+	assert.True(t, IsEmail(in.Email))
+
+	bad := struct {
+		Name string `sanitize:"unknown"`
+	}{}
+	assert.Error(t, SanitizeStruct(&bad))
+	assert.Error(t, SanitizeStruct(in))
+}
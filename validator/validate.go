@@ -0,0 +1,187 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/enverbisevac/libs/errors"
+)
+
+// ValidateStruct checks fields against rules listed in the `validate`
+// tag, for example:
+//
+//	type Signup struct {
+//		Name  string `json:"name" validate:"required,max=50"`
+//		Email string `json:"email" validate:"required,email"`
+//		Role  string `json:"role" validate:"oneof=admin editor"`
+//		Tags  []string `json:"tags" validate:"max=10"`
+//	}
+//
+// Supported rules are:
+//   - required: value is not zero, strings must not be blank
+//   - min=N, max=N: number of runes of strings, length of slices and
+//     maps or value of numbers
+//   - email, url: string is a valid email or absolute URL
+//   - oneof=a b c: value is one of space separated values
+//
+// Rules other than required are not checked on zero values, nil
+// pointers are zero values. Failures are returned as ValidationError
+// with errors.FieldError entries, field names are taken from the json
+// tag and nested structs are prefixed with the parent field name, for
+// example address.city. v must be a pointer to struct.
+func ValidateStruct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("validate: expected pointer to struct, got %T", v)
+	}
+	validator := New()
+	if err := validateStruct(validator, rv.Elem(), ""); err != nil {
+		return err
+	}
+	return validator.Err("validation failed")
+}
+
+func validateStruct(v *Validator, rv reflect.Value, prefix string) error {
+	typ := rv.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		field := rv.Field(i)
+		name := fieldName(sf, prefix)
+
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			if err := validateField(v, field, name, tag); err != nil {
+				return fmt.Errorf("validate: field %s: %w", sf.Name, err)
+			}
+		}
+
+		if field.Kind() == reflect.Pointer && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct {
+			// embedded structs and request bodies are not prefixed
+			nested := name
+			if _, body := sf.Tag.Lookup("body"); sf.Anonymous || body {
+				nested = prefix
+			}
+			if err := validateStruct(v, field, nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldName returns name of the field used in errors, the json tag
+// name or the struct field name.
+func fieldName(sf reflect.StructField, prefix string) string {
+	name := sf.Name
+	if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag != "" && tag != "-" {
+		name = tag
+	}
+	if prefix != "" {
+		name = prefix + "." + name
+	}
+	return name
+}
+
+func validateField(v *Validator, field reflect.Value, name, tag string) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field = reflect.Value{}
+		} else {
+			field = field.Elem()
+		}
+	}
+	zero := !field.IsValid() || field.IsZero() ||
+		(field.Kind() == reflect.String && !NotBlank(field.String()))
+
+	for _, rule := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if rule == "required" {
+			if zero {
+				v.AddError(&errors.FieldError{Field: name, Message: "is required", Code: rule})
+				return nil
+			}
+			continue
+		}
+		if zero {
+			continue
+		}
+		msg, err := checkRule(field, rule, param)
+		if err != nil {
+			return err
+		}
+		if msg != "" {
+			v.AddError(&errors.FieldError{Field: name, Message: msg, Code: rule})
+		}
+	}
+	return nil
+}
+
+// checkRule returns failure message of the rule or empty string when
+// field satisfies it.
+func checkRule(field reflect.Value, rule, param string) (string, error) {
+	switch rule {
+	case "min", "max":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s parameter %q", rule, param)
+		}
+		size, unit, err := sizeOf(field)
+		if err != nil {
+			return "", err
+		}
+		if rule == "min" && size < n {
+			return fmt.Sprintf("must be at least %s%s", param, unit), nil
+		}
+		if rule == "max" && size > n {
+			return fmt.Sprintf("must be at most %s%s", param, unit), nil
+		}
+	case "email":
+		if field.Kind() != reflect.String {
+			return "", fmt.Errorf("email rule requires string, got %v", field.Type())
+		}
+		if !IsEmail(field.String()) {
+			return "must be a valid email", nil
+		}
+	case "url":
+		if field.Kind() != reflect.String {
+			return "", fmt.Errorf("url rule requires string, got %v", field.Type())
+		}
+		if !IsURL(field.String()) {
+			return "must be a valid URL", nil
+		}
+	case "oneof":
+		values := strings.Fields(param)
+		if !In(fmt.Sprint(field.Interface()), values...) {
+			return fmt.Sprintf("must be one of [%s]", strings.Join(values, ", ")), nil
+		}
+	default:
+		return "", fmt.Errorf("unknown rule %q", rule)
+	}
+	return "", nil
+}
+
+// sizeOf returns the value compared by min and max rules and the unit
+// used in messages.
+func sizeOf(field reflect.Value) (float64, string, error) {
+	switch field.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(field.String())), " characters", nil
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(field.Len()), " items", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), "", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), "", nil
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), "", nil
+	}
+	return 0, "", fmt.Errorf("min and max rules are not supported on %v", field.Type())
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/enverbisevac/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStruct(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"required"`
+	}
+	type signup struct {
+		Name    string   `json:"name" validate:"required,max=5"`
+		Email   string   `json:"email" validate:"email"`
+		Role    string   `json:"role" validate:"oneof=admin editor"`
+		Age     *int     `json:"age" validate:"min=18"`
+		Tags    []string `validate:"max=2"`
+		Address address  `json:"address"`
+	}
+
+	age := 18
+	assert.NoError(t, ValidateStruct(&signup{
+		Name: "Jane", Role: "admin", Age: &age, Address: address{City: "Sarajevo"},
+	}))
+
+	age = 17
+	err := ValidateStruct(&signup{
+		Name:  "  ",
+		Email: "jane",
+		Role:  "owner",
+		Age:   &age,
+		Tags:  []string{"a", "b", "c"},
+	})
+	verr, ok := errors.AsValidation(err)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, errors.MarshalableErrors{
+		&errors.FieldError{Field: "name", Message: "is required", Code: "required"},
+		&errors.FieldError{Field: "email", Message: "must be a valid email", Code: "email"},
+		&errors.FieldError{Field: "role", Message: "must be one of [admin, editor]", Code: "oneof"},
+		&errors.FieldError{Field: "age", Message: "must be at least 18", Code: "min"},
+		&errors.FieldError{Field: "Tags", Message: "must be at most 2 items", Code: "max"},
+		&errors.FieldError{Field: "address.city", Message: "is required", Code: "required"},
+	}, verr.Errors)
+
+	err = ValidateStruct(&struct {
+		Name string `validate:"required,shout"`
+	}{Name: "x"})
+	assert.ErrorContains(t, err, `unknown rule "shout"`)
+	assert.False(t, errors.IsValidation(err))
+
+	assert.Error(t, ValidateStruct(signup{}))
+}
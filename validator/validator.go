@@ -1,10 +1,15 @@
 package validator
 
 import (
+	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/enverbisevac/libs/errors"
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type ValidatorFunc[T any] func(T) error
@@ -14,6 +19,11 @@ type Validator struct {
 	Errors []error
 }
 
+// New returns an empty Validator.
+func New() *Validator {
+	return &Validator{}
+}
+
 func (v *Validator) HasErrors() bool {
 	v.mux.Lock()
 	defer v.mux.Unlock()
@@ -48,6 +58,32 @@ func (v *Validator) Check(ok bool, err error) {
 	}
 }
 
+// CheckField adds errors.FieldError for the field with msg when ok is
+// false, for example:
+//
+//	v := validator.New()
+//	v.CheckField(validator.NotBlank(name), "name", "required")
+//	v.CheckField(validator.IsEmail(email), "email", "must be a valid email")
+//	return v.Err("invalid user")
+func (v *Validator) CheckField(ok bool, field, msg string) {
+	if !ok {
+		v.AddError(&errors.FieldError{Field: field, Message: msg})
+	}
+}
+
+// FieldErrors returns messages of field errors grouped by field name.
+func (v *Validator) FieldErrors() map[string][]string {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	result := make(map[string][]string)
+	for _, err := range v.Errors {
+		if ferr, ok := err.(*errors.FieldError); ok {
+			result[ferr.Field] = append(result[ferr.Field], ferr.Message)
+		}
+	}
+	return result
+}
+
 func (v *Validator) Err(msg string) error {
 	if v.HasErrors() {
 		return &errors.ValidationError{
@@ -71,6 +107,43 @@ func Validate[T any](data T, validators ...ValidatorFunc[T]) error {
 	return nil
 }
 
+// Dive returns validator which validates every element of a slice
+// with elementValidators, for example:
+//
+//	validateTags := validator.Dive(notBlank, isSlug)
+//	err := validateTags([]string{"go", " ", "Not A Slug"})
+//
+// Failing elements are reported as errors.FieldError with the element
+// index as the field, the error is a ValidationError.
+func Dive[T any](elementValidators ...ValidatorFunc[T]) ValidatorFunc[[]T] {
+	return func(values []T) error {
+		v := New()
+		for i, value := range values {
+			if err := Validate(value, elementValidators...); err != nil {
+				v.AddError(&errors.FieldError{Field: strconv.Itoa(i), Message: err.Error()})
+			}
+		}
+		return v.Err("invalid elements")
+	}
+}
+
+// DiveMap returns validator which validates every value of a map with
+// elementValidators. Failing values are reported as errors.FieldError
+// with the key as the field, in key order.
+func DiveMap[K constraints.Ordered, V any](elementValidators ...ValidatorFunc[V]) ValidatorFunc[map[K]V] {
+	return func(values map[K]V) error {
+		keys := maps.Keys(values)
+		slices.Sort(keys)
+		v := New()
+		for _, key := range keys {
+			if err := Validate(values[key], elementValidators...); err != nil {
+				v.AddError(&errors.FieldError{Field: fmt.Sprint(key), Message: err.Error()})
+			}
+		}
+		return v.Err("invalid elements")
+	}
+}
+
 func FromError(err error) (v *Validator) {
 	v = new(Validator)
 	verr, ok := errors.AsValidation(err)
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/enverbisevac/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator_CheckField(t *testing.T) {
+	type signup struct {
+		Name     string
+		Email    string
+		Password string
+	}
+
+	validate := func(in signup) error {
+		v := New()
+		v.CheckField(NotBlank(in.Name), "name", "required")
+		v.CheckField(IsEmail(in.Email), "email", "must be a valid email")
+		v.CheckField(MinRunes(in.Password, 8), "password", "must be at least 8 characters")
+		v.CheckField(NotIn(in.Password, "password", "12345678"), "password", "is too common")
+		return v.Err("invalid signup")
+	}
+
+	assert.NoError(t, validate(signup{Name: "Jane", Email: "jane@example.com", Password: "s3cr3t-pass"}))
+
+	err := validate(signup{Name: " ", Email: "jane@example.com", Password: "password"})
+	verr, ok := errors.AsValidation(err)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "invalid signup", verr.Error())
+	assert.Len(t, verr.Errors, 2)
+	assert.EqualError(t, verr.Errors[0], "name: required")
+	assert.EqualError(t, verr.Errors[1], "password: is too common")
+
+	v := FromError(err)
+	assert.Equal(t, map[string][]string{
+		"name":     {"required"},
+		"password": {"is too common"},
+	}, v.FieldErrors())
+}
+
+func TestValidator_JSONResponse(t *testing.T) {
+	v := New()
+	v.CheckField(NotBlank(""), "name", "required")
+	v.Check(false, errors.New("too many fields"))
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, errors.JSONResponse(w, v.Err("invalid signup")))
+
+	var response errors.HttpResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []errors.FieldError{
+		{Field: "name", Message: "required"},
+		{Message: "too many fields"},
+	}, response.Errors)
+}
+
+func TestDive(t *testing.T) {
+	notBlank := func(value string) error {
+		if !NotBlank(value) {
+			return errors.New("must not be blank")
+		}
+		return nil
+	}
+	isSlug := func(value string) error {
+		if !Matches(value, regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)) {
+			return errors.New("must be a valid slug")
+		}
+		return nil
+	}
+
+	validateTags := Dive[string](notBlank, isSlug)
+	assert.NoError(t, validateTags([]string{"go", "http-utils"}))
+	assert.NoError(t, validateTags(nil))
+
+	err := validateTags([]string{"go", " ", "Not A Slug", "ok"})
+	assert.True(t, errors.IsValidation(err))
+	assert.Equal(t, map[string][]string{
+		"1": {"must not be blank"},
+		"2": {"must be a valid slug"},
+	}, FromError(err).FieldErrors())
+
+	validateLabels := DiveMap[string](notBlank)
+	err = validateLabels(map[string]string{"b": "", "a": " ", "c": "x"})
+	verr, _ := errors.AsValidation(err)
+	assert.Equal(t, errors.MarshalableErrors{
+		&errors.FieldError{Field: "a", Message: "must not be blank"},
+		&errors.FieldError{Field: "b", Message: "must not be blank"},
+	}, verr.Errors)
+}